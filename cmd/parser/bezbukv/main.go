@@ -1,130 +1,79 @@
 package main
 
 import (
-	"errors"
+	"context"
+	"flag"
+	"fmt"
 	"github.com/Markard/wordka/config"
+	"github.com/Markard/wordka/internal/entity"
 	"github.com/Markard/wordka/internal/repo"
+	"github.com/Markard/wordka/internal/usecase/dictionary"
 	"github.com/Markard/wordka/pkg/postgres"
 	"github.com/Markard/wordka/pkg/slogext"
+	"github.com/Markard/wordka/pkg/wordsource"
+	"github.com/Markard/wordka/pkg/wordsource/bezbukv"
+	"github.com/Markard/wordka/pkg/wordsource/htmldump"
 	"github.com/PuerkitoBio/goquery"
-	"log/slog"
-	"net/http"
-	"regexp"
-	"strconv"
-	"strings"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
 	"time"
 )
 
-type Job struct {
-	Id  int
-	Url string
-}
-
-type Result struct {
-	JobId int
-	Words []string
-}
-
-type FiveLetterNounParser struct {
-	pages   int
-	workers int
-	baseUrl string
-	results chan<- *Result
-}
-
-func ParserWithPagination(pages int, workers int, baseUrl string, results chan<- *Result) *FiveLetterNounParser {
-	return &FiveLetterNounParser{pages: pages, workers: workers, baseUrl: baseUrl, results: results}
-}
-
-func (p *FiveLetterNounParser) Parse() {
-	jobs := make(chan *Job, p.pages)
-
-	for w := 1; w <= p.workers; w++ {
-		worker := NewWorker(w, jobs, p.results)
-		go worker.Start()
-	}
+func main() {
+	length := flag.Int("length", 5, "length of the nouns to harvest, from 4 to 8")
+	pages := flag.Int("pages", 0, "number of listing pages, discovered from the first page if 0")
+	workers := flag.Int("workers", 3, "number of pages fetched concurrently")
+	interval := flag.Duration("interval", time.Second, "pause between two requests to the site")
+	retries := flag.Int("retries", 5, "number of retries of a page that failed to load")
+	timeout := flag.Duration("timeout", 10*time.Second, "timeout of a single request")
+	baseUrl := flag.String("base-url", bezbukv.DefaultBaseUrl, "site to harvest, e.g. a local server with saved pages")
+	dump := flag.String("dump", "", "directory with saved listing pages to harvest instead of the site")
+	checkpointPath := flag.String("checkpoint", "", "file tracking the saved pages, a file in the temp dir if empty")
+	flag.Parse()
 
-	for j := 1; j <= p.pages; j++ {
-		job := &Job{
-			Id:  j,
-			Url: p.baseUrl + strconv.Itoa(j),
-		}
-		jobs <- job
+	setup := config.MustLoad()
+	logger := slogext.SetupLogger(setup.Env.AppEnv)
+	if *length < entity.MinWordLength || *length > entity.MaxWordLength {
+		slogext.Fatal(logger, fmt.Errorf("unsupported word length %d", *length))
 	}
-	close(jobs)
-}
-
-type Worker struct {
-	Id      int
-	Jobs    <-chan *Job
-	Results chan<- *Result
-}
-
-func NewWorker(id int, jobs <-chan *Job, results chan<- *Result) *Worker {
-	return &Worker{
-		Id:      id,
-		Jobs:    jobs,
-		Results: results,
+	if *checkpointPath == "" {
+		*checkpointPath = filepath.Join(os.TempDir(), fmt.Sprintf("wordka-bezbukv-%d.json", *length))
 	}
-}
 
-func (w *Worker) Start() {
-	for job := range w.Jobs {
-		w.Results <- w.DoWork(job)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var source wordsource.Source
+	var checkpointKey string
+	if *dump != "" {
+		pattern := bezbukv.Pattern(*length)
+		source = htmldump.New(bezbukv.Name, *dump, func(doc *goquery.Document) []string {
+			return bezbukv.Extract(doc, pattern)
+		})
+		checkpointKey = *dump
+	} else {
+		fetcher := wordsource.NewHttpFetcher(wordsource.FetcherOptions{
+			Retries:  *retries,
+			Interval: *interval,
+			Timeout:  *timeout,
+		}, logger)
+		site := bezbukv.New(fetcher, *baseUrl, *length, *pages)
+		source = site
+		checkpointKey = site.ListingUrl()
 	}
-}
 
-func (w *Worker) DoWork(job *Job) *Result {
-	logger := slog.Default()
-	logger.Info("Fetching words", "url", job.Url)
-	client := &http.Client{
-		Timeout: time.Second * 5,
-	}
-	resp, err := client.Get(job.Url)
+	listing, err := source.Paginate(ctx)
 	if err != nil {
 		slogext.Fatal(logger, err)
 	}
-	defer func() { _ = resp.Body.Close() }()
-	if resp.StatusCode != 200 {
-		slogext.Fatal(logger, errors.New(resp.Status))
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	checkpoint, err := wordsource.LoadCheckpoint(*checkpointPath, checkpointKey)
 	if err != nil {
 		slogext.Fatal(logger, err)
 	}
-
-	result := &Result{
-		JobId: job.Id,
-		Words: make([]string, 0),
-	}
-	doc.Find("div.view").Each(func(i int, s *goquery.Selection) {
-		re := regexp.MustCompile(`[а-яА-ЯёЁ-]{5}`)
-		lines := strings.Split(s.Text(), "\n")
-		for _, line := range lines {
-			matches := re.FindStringSubmatch(line)
-			if len(matches) == 1 {
-				word := strings.ToLower(matches[0])
-				result.Words = append(result.Words, word)
-			}
-		}
-	})
-
-	return result
-}
-
-func main() {
-	setup := config.MustLoad()
-	logger := slogext.SetupLogger(setup.Env.AppEnv)
-	pages := 36
-	results := make(chan *Result, pages)
-	parser := ParserWithPagination(
-		pages,
-		5,
-		"https://bezbukv.ru/mask/%2A%2A%2A%2A%2A/noun?page=",
-		results,
-	)
-	parser.Parse()
+	pending := checkpoint.Pending(listing)
+	logger.Info("Bezbukv:Start", "pages", len(listing), "pending", len(pending), "checkpoint", *checkpointPath)
 
 	db := postgres.New(setup.Env.PgDSN, logger)
 	defer func() {
@@ -133,15 +82,43 @@ func main() {
 			slogext.Error(logger, err)
 		}
 	}()
-	gameRepo := repo.NewGameRepository(db)
+	dictionaryUseCase := dictionary.NewDictionaryUseCase(repo.NewDictionaryRepository(db))
+
+	saved := wordsource.NewSavedWords()
+	results := wordsource.Dedup(wordsource.Normalize(
+		wordsource.Harvest(ctx, source, pending, *workers),
+		entity.NormalizeWord,
+	), saved)
+	failed := 0
+	for result := range results {
+		if result.Err != nil {
+			slogext.Error(logger, fmt.Errorf("page %d: %w", result.Page.Number, result.Err))
+			failed++
+			continue
+		}
+		if len(result.Words) > 0 {
+			if err := dictionaryUseCase.SaveFoundWords(result.Source, result.Words); err != nil {
+				slogext.Error(logger, fmt.Errorf("page %d: %w", result.Page.Number, err))
+				failed++
+				continue
+			}
+			saved.Add(result.Words)
+		}
+		if err := checkpoint.MarkDone(result.Page); err != nil {
+			slogext.Error(logger, err)
+		}
+		logger.Info("Bezbukv:Saved", "page", result.Page.Number, "words", len(result.Words))
+	}
 
-	for r := 1; r <= pages; r++ {
-		result := <-results
-		err := gameRepo.SaveWords(result.Words)
-		if err != nil {
+	switch {
+	case ctx.Err() != nil:
+		logger.Info("Bezbukv:Interrupted", "checkpoint", *checkpointPath)
+	case failed > 0:
+		logger.Warn("Bezbukv:Incomplete", "failed_pages", failed, "checkpoint", *checkpointPath)
+	default:
+		if err := checkpoint.Remove(); err != nil {
 			slogext.Error(logger, err)
 		}
-		logger.Info("Successfully fetched", "words", len(result.Words))
+		logger.Info("Bezbukv:Done", "pages", len(listing))
 	}
-	close(results)
 }
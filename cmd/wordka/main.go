@@ -3,10 +3,16 @@ package main
 import (
 	"github.com/Markard/wordka/config"
 	"github.com/Markard/wordka/internal/app"
+	"os"
 )
 
 func main() {
 	setup := config.MustLoad()
 
+	if len(os.Args) > 1 {
+		app.RunCommand(setup, os.Args[1:])
+		return
+	}
+
 	app.Run(setup)
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog/log"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -17,6 +18,12 @@ type (
 
 	Config struct {
 		HttpServer HttpServer `yaml:"http_server"`
+		Game       Game       `yaml:"game"`
+		Events     Events     `yaml:"events"`
+		Auth       Auth       `yaml:"auth"`
+		Mail       Mail       `yaml:"mail"`
+		RateLimit  RateLimit  `yaml:"rate_limit"`
+		Oidc       Oidc       `yaml:"oidc"`
 	}
 
 	HttpServer struct {
@@ -25,6 +32,98 @@ type (
 		IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"30s"`
 	}
 
+	Game struct {
+		DailyTimezone string    `yaml:"daily_timezone" env-default:"Europe/Moscow"`
+		Practice      GameRules `yaml:"practice"`
+		Daily         GameRules `yaml:"daily"`
+		Duel          GameRules `yaml:"duel"`
+	}
+
+	GameRules struct {
+		WordLength int  `yaml:"word_length" env-default:"5"`
+		GuessLimit int8 `yaml:"guess_limit" env-default:"6"`
+	}
+
+	Events struct {
+		Backend string `yaml:"backend" env-default:"memory"`
+	}
+
+	Auth struct {
+		// Issuer and Audience are put into the tokens and required from them,
+		// they have to differ between the environments, so a dev token is rejected in prod.
+		Issuer          string        `yaml:"issuer" env-required:"true"`
+		Audience        string        `yaml:"audience" env-required:"true"`
+		AccessTokenTtl  time.Duration `yaml:"access_token_ttl" env-default:"15m"`
+		RefreshTokenTtl time.Duration `yaml:"refresh_token_ttl" env-default:"720h"`
+		// TokenLeeway tolerates the clock skew between servers when checking the times of a token.
+		TokenLeeway time.Duration `yaml:"token_leeway" env-default:"30s"`
+		// RequireVerifiedEmail keeps users who have not verified their email out of the gameplay routes.
+		RequireVerifiedEmail     bool          `yaml:"require_verified_email" env-default:"false"`
+		VerificationUrl          string        `yaml:"verification_url" env-required:"true"`
+		VerificationTokenTtl     time.Duration `yaml:"verification_token_ttl" env-default:"24h"`
+		VerificationResendPeriod time.Duration `yaml:"verification_resend_period" env-default:"1m"`
+		PasswordResetUrl         string        `yaml:"password_reset_url" env-required:"true"`
+		PasswordResetTokenTtl    time.Duration `yaml:"password_reset_token_ttl" env-default:"1h"`
+		// KeyDir holds the keys signing the tokens, see "wordka keys rotate". Until keys are rotated into it,
+		// the ES256 key pair of the environment is used.
+		KeyDir string `yaml:"key_dir"`
+		// LockoutThreshold failed logins in a row lock the account out for LockoutDuration,
+		// doubled with every further failure up to LockoutMaxDuration. Zero disables the lockout.
+		LockoutThreshold   int           `yaml:"lockout_threshold" env-default:"5"`
+		LockoutDuration    time.Duration `yaml:"lockout_duration" env-default:"1m"`
+		LockoutMaxDuration time.Duration `yaml:"lockout_max_duration" env-default:"1h"`
+		// UniformRegistration answers registrations with 202 whether the email is taken or not,
+		// so they do not reveal who is registered. The owner of a taken email is notified instead.
+		UniformRegistration bool `yaml:"uniform_registration" env-default:"false"`
+	}
+
+	Mail struct {
+		// Backend is "smtp" to deliver emails, "file" to save them to Dir or "log" to only log them.
+		Backend  string `yaml:"backend" env-default:"log"`
+		From     string `yaml:"from" env-required:"true"`
+		Dir      string `yaml:"dir" env-default:"tmp/mail"`
+		SmtpHost string `yaml:"smtp_host"`
+		SmtpPort int    `yaml:"smtp_port" env-default:"587"`
+		SmtpUser string `yaml:"smtp_user"`
+	}
+
+	RateLimit struct {
+		// Backend is "postgres" to share the limits between app instances or "memory" to limit every instance on its own.
+		Backend string `yaml:"backend" env-default:"memory"`
+		// TrustProxy takes the client IP from the X-Forwarded-For and X-Real-IP headers set by a reverse proxy.
+		TrustProxy bool `yaml:"trust_proxy" env-default:"false"`
+		// Ip limits the requests of a client IP to the public auth endpoints.
+		Ip RateLimitRule `yaml:"ip"`
+		// Email limits the logins, registrations and password recoveries for an email, whichever clients make them.
+		Email RateLimitRule `yaml:"email"`
+	}
+
+	// RateLimitRule allows Burst requests at once and Requests per Period on average. Zero Requests disable it.
+	RateLimitRule struct {
+		Requests int           `yaml:"requests"`
+		Period   time.Duration `yaml:"period" env-default:"1m"`
+		Burst    int           `yaml:"burst"`
+	}
+
+	Oidc struct {
+		// LoginTtl is how long the users have to log in at a provider and come back.
+		LoginTtl  time.Duration  `yaml:"login_ttl" env-default:"10m"`
+		Providers []OidcProvider `yaml:"providers"`
+	}
+
+	// OidcProvider is the app registered as a client of an OpenID Connect provider. The client secret is taken
+	// from the OIDC_<NAME>_CLIENT_SECRET variable. The endpoints are discovered from the issuer unless they are set.
+	OidcProvider struct {
+		Name             string   `yaml:"name"`
+		Issuer           string   `yaml:"issuer"`
+		ClientId         string   `yaml:"client_id"`
+		RedirectUrl      string   `yaml:"redirect_url"`
+		Scopes           []string `yaml:"scopes"`
+		AuthorizationUrl string   `yaml:"authorization_url"`
+		TokenUrl         string   `yaml:"token_url"`
+		JwksUrl          string   `yaml:"jwks_url"`
+	}
+
 	Env struct {
 		AppEnv          string
 		ES256PrivateKey string
@@ -35,10 +134,13 @@ type (
 		PgPort          string
 		PgHost          string
 		PgDSN           string
+		SmtpPass        string
+		// OidcClientSecrets are the client secrets of the OpenID Connect providers by their names.
+		OidcClientSecrets map[string]string
 	}
 )
 
-func newEnv(appEnv, eS256PrivateKey, eS256PublicKey, pgDb, pgUser, pgPass, pgPort, pgHost string) *Env {
+func newEnv(appEnv, eS256PrivateKey, eS256PublicKey, pgDb, pgUser, pgPass, pgPort, pgHost, smtpPass string) *Env {
 	env := &Env{
 		AppEnv:          appEnv,
 		ES256PrivateKey: eS256PrivateKey,
@@ -48,6 +150,7 @@ func newEnv(appEnv, eS256PrivateKey, eS256PublicKey, pgDb, pgUser, pgPass, pgPor
 		PgPass:          pgPass,
 		PgPort:          pgPort,
 		PgHost:          pgHost,
+		SmtpPass:        smtpPass,
 	}
 	env.PgDSN = fmt.Sprintf("postgres://%s:%s@%s:%s/%s", env.PgUser, env.PgPass, env.PgHost, env.PgPort, env.PgDb)
 
@@ -73,6 +176,7 @@ func MustLoad() *Setup {
 		os.Getenv("PG_PASS"),
 		os.Getenv("PG_PORT"),
 		os.Getenv("PG_HOST"),
+		os.Getenv("SMTP_PASS"),
 	)
 
 	configPath := fmt.Sprintf("%s/config/%s.yaml", currentDir, env.AppEnv)
@@ -84,6 +188,17 @@ func MustLoad() *Setup {
 	if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
 		log.Fatal().Err(err).Msg("failed to read config")
 	}
+	env.OidcClientSecrets = oidcClientSecrets(cfg.Oidc.Providers)
 
 	return &Setup{&cfg, env}
 }
+
+func oidcClientSecrets(providers []OidcProvider) map[string]string {
+	secrets := make(map[string]string, len(providers))
+	for _, provider := range providers {
+		name := strings.ToUpper(strings.ReplaceAll(provider.Name, "-", "_"))
+		secrets[provider.Name] = os.Getenv(fmt.Sprintf("OIDC_%s_CLIENT_SECRET", name))
+	}
+
+	return secrets
+}
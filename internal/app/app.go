@@ -1,24 +1,39 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/Markard/wordka/config"
 	"github.com/Markard/wordka/internal/controller/http"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/infra/eventbus"
+	"github.com/Markard/wordka/internal/infra/mailer"
 	"github.com/Markard/wordka/internal/infra/middleware"
 	"github.com/Markard/wordka/internal/infra/middleware/jwt"
+	"github.com/Markard/wordka/internal/infra/ratelimit"
 	serviceJwt "github.com/Markard/wordka/internal/infra/service/jwt"
 	"github.com/Markard/wordka/internal/repo"
 	"github.com/Markard/wordka/internal/usecase"
+	"github.com/Markard/wordka/internal/usecase/admin"
 	"github.com/Markard/wordka/internal/usecase/auth"
+	"github.com/Markard/wordka/internal/usecase/duel"
 	"github.com/Markard/wordka/internal/usecase/game"
+	"github.com/Markard/wordka/internal/usecase/leaderboard"
+	"github.com/Markard/wordka/internal/usecase/profile"
+	"github.com/Markard/wordka/internal/usecase/share"
+	"github.com/Markard/wordka/internal/usecase/stats"
 	"github.com/Markard/wordka/pkg/http/server"
 	"github.com/Markard/wordka/pkg/http/validator"
+	"github.com/Markard/wordka/pkg/oidc"
 	"github.com/Markard/wordka/pkg/postgres"
 	"github.com/Markard/wordka/pkg/slogext"
 	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+	_ "time/tzdata"
 )
 
 func Run(setup *config.Setup) {
@@ -38,27 +53,115 @@ func Run(setup *config.Setup) {
 			slogext.Fatal(logger, err)
 		}
 	}()
+	dailyLocation, err := time.LoadLocation(setup.Config.Game.DailyTimezone)
+	if err != nil {
+		slogext.Fatal(logger, err)
+	}
+
 	authRepo := repo.NewAuthRepository(db)
-	gameRepo := repo.NewGameRepository(db)
+	gameRepo := repo.NewGameRepository(db, dailyLocation)
+	statsRepo := repo.NewStatsRepository(db)
+	leaderboardRepo := repo.NewLeaderboardRepository(db, dailyLocation)
+	duelRepo := repo.NewDuelRepository(db)
+	shareRepo := repo.NewShareRepository(db)
+	sessionRepo := repo.NewSessionRepository(db)
+	profileRepo := repo.NewProfileRepository(db)
+	adminRepo := repo.NewAdminRepository(db)
+	identityRepo := repo.NewIdentityRepository(db)
+
+	// Event bus
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var bus eventbus.Bus
+	switch setup.Config.Events.Backend {
+	case "postgres":
+		pgBus := eventbus.NewPostgresBus(db, logger)
+		if err := pgBus.Start(ctx); err != nil {
+			slogext.Fatal(logger, err)
+		}
+		bus = pgBus
+	default:
+		bus = eventbus.NewMemoryBus(logger)
+	}
+
+	// Mailer
+	var mail mailer.Mailer
+	switch setup.Config.Mail.Backend {
+	case "smtp":
+		mail = mailer.NewSmtpMailer(
+			setup.Config.Mail.From,
+			setup.Config.Mail.SmtpHost,
+			setup.Config.Mail.SmtpPort,
+			setup.Config.Mail.SmtpUser,
+			setup.Env.SmtpPass,
+		)
+	case "file":
+		fileMailer, err := mailer.NewFileMailer(setup.Config.Mail.From, setup.Config.Mail.Dir)
+		if err != nil {
+			slogext.Fatal(logger, err)
+		}
+		mail = fileMailer
+	default:
+		mail = mailer.NewLogMailer(logger)
+	}
+
+	// Signing keys
+	keys, err := loadKeys(setup)
+	if err != nil {
+		slogext.Fatal(logger, err)
+	}
 
 	// Use cases
-	jwtService := serviceJwt.NewService(setup.Env.ES256PrivateKey, setup.Env.ES256PublicKey)
+	practiceRules := gameRules(setup.Config.Game.Practice)
+	dailyRules := gameRules(setup.Config.Game.Daily)
+	duelRules := gameRules(setup.Config.Game.Duel)
+	jwtService := serviceJwt.NewService(keys, jwtOptions(setup.Config.Auth))
+	authUseCase := auth.NewAuth(
+		authRepo,
+		sessionRepo,
+		identityRepo,
+		jwtService,
+		mail,
+		oidcProviders(setup),
+		authOptions(setup.Config.Auth, setup.Config.Oidc),
+	)
 	useCases := &usecase.UseCases{
-		AuthUseCase: auth.NewAuth(authRepo, jwtService),
-		GameUseCase: game.NewGameUseCase(gameRepo),
+		AuthUseCase:        authUseCase,
+		GameUseCase:        game.NewGameUseCase(gameRepo, bus, dailyLocation, practiceRules, dailyRules),
+		StatsUseCase:       stats.NewStatsUseCase(statsRepo),
+		LeaderboardUseCase: leaderboard.NewLeaderboardUseCase(leaderboardRepo, gameRepo, dailyLocation),
+		DuelUseCase:        duel.NewDuelUseCase(duelRepo, gameRepo, bus, duelRules),
+		ShareUseCase:       share.NewShareUseCase(shareRepo, gameRepo),
+		ProfileUseCase:     profile.NewProfileUseCase(authRepo, profileRepo, sessionRepo, authUseCase),
+		AdminUseCase:       admin.NewAdminUseCase(adminRepo, sessionRepo, dailyLocation, dailyRules),
 	}
 
 	// Middleware
+	var rateLimitStore ratelimit.Store
+	switch setup.Config.RateLimit.Backend {
+	case "postgres":
+		rateLimitStore = ratelimit.NewPostgresStore(db)
+	default:
+		rateLimitStore = ratelimit.NewMemoryStore()
+	}
+	limiter := ratelimit.NewLimiter(rateLimitStore, logger)
 	middlewares := &middleware.Middlewares{
-		JwtAuthenticator: jwt.Authenticator(jwtService, authRepo, logger),
+		JwtAuthenticator: jwt.Authenticator(jwtService, authRepo, sessionRepo, logger),
+		GameplayGuard:    middleware.Pass,
+		IpRateLimit:      limiter.ByIp("ip", rateLimit(setup.Config.RateLimit.Ip)),
+		EmailRateLimit:   limiter.ByEmail("email", rateLimit(setup.Config.RateLimit.Email)),
+	}
+	if setup.Config.Auth.RequireVerifiedEmail {
+		middlewares.GameplayGuard = jwt.VerifiedEmail
 	}
 
 	// HTTP Server
 	httpServer := server.New(setup.Config.HttpServer.Address, setup.Config.HttpServer.IdleTimeout)
-	http.SetupRouter(httpServer.Router, setup, val, middlewares, useCases)
+	http.SetupRouter(httpServer.Router, setup, val, middlewares, useCases, bus)
 
 	// Start Http Server
 	httpServer.Start()
+	go useCases.GameUseCase.WatchDailyRollover(ctx)
 	logger.Info("Wordka:Start", "address", setup.Config.HttpServer.Address, "env", setup.Env.AppEnv)
 
 	// Waiting signal
@@ -73,9 +176,80 @@ func Run(setup *config.Setup) {
 	}
 
 	// Shutdown
+	cancel()
+	bus.Close()
 	err = httpServer.Shutdown()
 	if err != nil {
 		slogext.Error(logger, fmt.Errorf("Wordka:Shutdown | Error: %w", err))
 	}
 	logger.Info("Wordka:Shutdown")
 }
+
+func gameRules(rules config.GameRules) entity.GameRules {
+	return entity.GameRules{WordLength: rules.WordLength, GuessLimit: rules.GuessLimit}
+}
+
+// loadKeys takes the keys from the key directory, or the key pair from the environment
+// until keys are rotated into the directory.
+func loadKeys(setup *config.Setup) (*serviceJwt.KeySet, error) {
+	if setup.Config.Auth.KeyDir != "" {
+		keys, err := serviceJwt.LoadKeyDir(setup.Config.Auth.KeyDir)
+		if !errors.Is(err, serviceJwt.ErrNoActiveKey) {
+			return keys, err
+		}
+	}
+	if setup.Env.ES256PrivateKey == "" {
+		return nil, errors.New("no signing key: set ES256_PRIVATE_KEY or run \"wordka keys rotate\"")
+	}
+
+	return serviceJwt.NewKeySetFromPem(setup.Env.ES256PrivateKey, setup.Env.ES256PublicKey)
+}
+
+func rateLimit(rule config.RateLimitRule) ratelimit.Limit {
+	return ratelimit.Limit{Requests: rule.Requests, Period: rule.Period, Burst: rule.Burst}
+}
+
+func jwtOptions(options config.Auth) serviceJwt.Options {
+	return serviceJwt.Options{
+		Issuer:   options.Issuer,
+		Audience: options.Audience,
+		Leeway:   options.TokenLeeway,
+	}
+}
+
+func authOptions(options config.Auth, oidcOptions config.Oidc) auth.Options {
+	return auth.Options{
+		AccessTokenTtl:           options.AccessTokenTtl,
+		RefreshTokenTtl:          options.RefreshTokenTtl,
+		VerificationUrl:          options.VerificationUrl,
+		VerificationTokenTtl:     options.VerificationTokenTtl,
+		VerificationResendPeriod: options.VerificationResendPeriod,
+		PasswordResetUrl:         options.PasswordResetUrl,
+		PasswordResetTokenTtl:    options.PasswordResetTokenTtl,
+		Lockout: entity.LoginLockout{
+			Threshold:   options.LockoutThreshold,
+			Duration:    options.LockoutDuration,
+			MaxDuration: options.LockoutMaxDuration,
+		},
+		UniformRegistration: options.UniformRegistration,
+		ExternalLoginTtl:    oidcOptions.LoginTtl,
+	}
+}
+
+func oidcProviders(setup *config.Setup) map[string]auth.IExternalProvider {
+	providers := make(map[string]auth.IExternalProvider, len(setup.Config.Oidc.Providers))
+	for _, provider := range setup.Config.Oidc.Providers {
+		providers[provider.Name] = oidc.NewProvider(oidc.Config{
+			Issuer:           provider.Issuer,
+			ClientId:         provider.ClientId,
+			ClientSecret:     setup.Env.OidcClientSecrets[provider.Name],
+			RedirectUrl:      provider.RedirectUrl,
+			Scopes:           provider.Scopes,
+			AuthorizationUrl: provider.AuthorizationUrl,
+			TokenUrl:         provider.TokenUrl,
+			JwksUrl:          provider.JwksUrl,
+		}, nil)
+	}
+
+	return providers
+}
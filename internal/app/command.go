@@ -0,0 +1,445 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/Markard/wordka/config"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/infra/dictfile"
+	serviceJwt "github.com/Markard/wordka/internal/infra/service/jwt"
+	"github.com/Markard/wordka/internal/repo"
+	"github.com/Markard/wordka/internal/usecase/dictionary"
+	"github.com/Markard/wordka/internal/usecase/leaderboard"
+	"github.com/Markard/wordka/internal/usecase/stats"
+	"github.com/Markard/wordka/pkg/oidc/mock"
+	"github.com/Markard/wordka/pkg/postgres"
+	"github.com/Markard/wordka/pkg/slogext"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+type command struct {
+	usage       string
+	description string
+	run         func(setup *config.Setup, logger *slog.Logger, args []string) error
+}
+
+// commands are maintenance tasks run as "wordka <group> <name> [flags]" instead of starting the server.
+var commands = map[string]*command{
+	"stats rebuild": {
+		usage:       "stats rebuild",
+		description: "Recalculate player statistics from the game history",
+		run:         rebuildStats,
+	},
+	"leaderboards rebuild": {
+		usage:       "leaderboards rebuild",
+		description: "Recalculate leaderboards from the game history",
+		run:         rebuildLeaderboards,
+	},
+	"dict import": {
+		usage:       "dict import [flags] FILE",
+		description: "Add the words of a local word list to the dictionary",
+		run:         importDictionary,
+	},
+	"words promote": {
+		usage:       "words promote WORD...",
+		description: "Make the words eligible to be answers",
+		run:         curateWords("Words:Promote", (*dictionary.UseCase).Promote),
+	},
+	"words demote": {
+		usage:       "words demote WORD...",
+		description: "Keep the words as accepted guesses only",
+		run:         curateWords("Words:Demote", (*dictionary.UseCase).Demote),
+	},
+	"words enable": {
+		usage:       "words enable WORD...",
+		description: "Accept the words as guesses again",
+		run:         curateWords("Words:Enable", (*dictionary.UseCase).Enable),
+	},
+	"words disable": {
+		usage:       "words disable WORD...",
+		description: "Neither accept the words as guesses nor pick them as answers",
+		run:         curateWords("Words:Disable", (*dictionary.UseCase).Disable),
+	},
+	"words single-source": {
+		usage:       "words single-source [flags]",
+		description: "List the words found in one source only, for a review",
+		run:         listSingleSourceWords,
+	},
+	"words flag": {
+		usage:       "words flag [flags] WORD...",
+		description: "Set -offensive and -difficulty of the words",
+		run:         flagWords,
+	},
+	"keys rotate": {
+		usage:       "keys rotate [flags]",
+		description: "Generate a new key signing the tokens, restart the app to use it",
+		run:         rotateKeys,
+	},
+	"oidc mock": {
+		usage:       "oidc mock [flags]",
+		description: "Run an OpenID Connect provider logging everybody in, for development",
+		run:         runMockProvider,
+	},
+	"users role": {
+		usage:       "users role EMAIL ROLE",
+		description: "Grant the user a role: " + strings.Join(entity.Roles, ", "),
+		run:         setUserRole,
+	},
+}
+
+// RunCommand executes the maintenance command named by the first two arguments.
+func RunCommand(setup *config.Setup, args []string) {
+	logger := slogext.SetupLogger(setup.Env.AppEnv)
+
+	if len(args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[args[0]+" "+args[1]]
+	if !ok {
+		printUsage()
+		os.Exit(2)
+	}
+
+	if err := cmd.run(setup, logger, args[2:]); err != nil {
+		slogext.Fatal(logger, err)
+	}
+}
+
+func printUsage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var b strings.Builder
+	b.WriteString("Usage:\n  wordka                              Start the HTTP server\n")
+	for _, name := range names {
+		_, _ = fmt.Fprintf(&b, "  wordka %-28s %s\n", commands[name].usage, commands[name].description)
+	}
+	_, _ = fmt.Fprint(os.Stderr, b.String())
+}
+
+func rebuildStats(setup *config.Setup, logger *slog.Logger, _ []string) error {
+	db := postgres.New(setup.Env.PgDSN, logger)
+	defer func() {
+		if err := db.Close(); err != nil {
+			slogext.Error(logger, err)
+		}
+	}()
+
+	users, err := stats.NewStatsUseCase(repo.NewStatsRepository(db)).RebuildStats()
+	if err != nil {
+		return err
+	}
+	logger.Info("Stats:Rebuild", "users", users)
+
+	return nil
+}
+
+func rebuildLeaderboards(setup *config.Setup, logger *slog.Logger, _ []string) error {
+	db := postgres.New(setup.Env.PgDSN, logger)
+	defer func() {
+		if err := db.Close(); err != nil {
+			slogext.Error(logger, err)
+		}
+	}()
+
+	dailyLocation, err := time.LoadLocation(setup.Config.Game.DailyTimezone)
+	if err != nil {
+		return err
+	}
+	useCase := leaderboard.NewLeaderboardUseCase(
+		repo.NewLeaderboardRepository(db, dailyLocation),
+		repo.NewGameRepository(db, dailyLocation),
+		dailyLocation,
+	)
+	if err := useCase.RebuildLeaderboards(); err != nil {
+		return err
+	}
+	logger.Info("Leaderboards:Rebuild")
+
+	return nil
+}
+
+// curateWords builds a command applying the dictionary change to every word given as an argument.
+func curateWords(
+	name string,
+	change func(useCase *dictionary.UseCase, word string) (*entity.Word, error),
+) func(setup *config.Setup, logger *slog.Logger, args []string) error {
+	return func(setup *config.Setup, logger *slog.Logger, args []string) error {
+		if len(args) == 0 {
+			return errors.New("no words given")
+		}
+
+		db := postgres.New(setup.Env.PgDSN, logger)
+		defer func() {
+			if err := db.Close(); err != nil {
+				slogext.Error(logger, err)
+			}
+		}()
+
+		useCase := dictionary.NewDictionaryUseCase(repo.NewDictionaryRepository(db))
+		for _, arg := range args {
+			word, err := change(useCase, arg)
+			if err != nil {
+				return fmt.Errorf("%s: %w", arg, err)
+			}
+			logger.Info(name, "word", word.Word, "is_enabled", word.IsEnabled, "is_answer", word.IsAnswer)
+		}
+
+		return nil
+	}
+}
+
+func flagWords(setup *config.Setup, logger *slog.Logger, args []string) error {
+	flags := flag.NewFlagSet("words flag", flag.ContinueOnError)
+	offensive := flags.Bool("offensive", false, "mark the words as offensive, which also demotes them")
+	difficulty := flags.Int("difficulty", 0, "rate the words from 1 to 5, 0 clears the rating")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	isSet := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { isSet[f.Name] = true })
+	if len(isSet) == 0 {
+		return errors.New("neither -offensive nor -difficulty given")
+	}
+
+	return curateWords("Words:Flag", func(useCase *dictionary.UseCase, wordStr string) (*entity.Word, error) {
+		var word *entity.Word
+		var err error
+		if isSet["offensive"] {
+			if word, err = useCase.MarkOffensive(wordStr, *offensive); err != nil {
+				return nil, err
+			}
+		}
+		if isSet["difficulty"] {
+			word, err = useCase.SetDifficulty(wordStr, *difficulty)
+		}
+
+		return word, err
+	})(setup, logger, flags.Args())
+}
+
+func importDictionary(setup *config.Setup, logger *slog.Logger, args []string) error {
+	flags := flag.NewFlagSet("dict import", flag.ContinueOnError)
+	format := flags.String("format", "", "one of "+strings.Join(dictfile.Formats, ", ")+", guessed by the file extension if empty")
+	answers := flags.Bool("answers", false, "make every added word eligible to be an answer")
+	source := flags.String("source", "", "name of the list recorded as the provenance of its words, file:NAME if empty")
+	dryRun := flags.Bool("dry-run", false, "print the report without saving anything")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("exactly one word list file expected")
+	}
+	path := flags.Arg(0)
+	if *source == "" {
+		*source = "file:" + filepath.Base(path)
+	}
+
+	if *format == "" {
+		var err error
+		if *format, err = dictfile.FormatOf(path); err != nil {
+			return err
+		}
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	entries, err := dictfile.Read(*format, file)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if *answers {
+		for i := range entries {
+			entries[i].IsAnswer = true
+		}
+	}
+
+	db := postgres.New(setup.Env.PgDSN, logger)
+	defer func() {
+		if err := db.Close(); err != nil {
+			slogext.Error(logger, err)
+		}
+	}()
+
+	report, err := dictionary.NewDictionaryUseCase(repo.NewDictionaryRepository(db)).Import(*source, entries, *dryRun)
+	if err != nil {
+		return err
+	}
+	printImportReport(report, *dryRun)
+
+	return nil
+}
+
+// printImportReport prints every entry of the word list prefixed the way a diff does:
+// "+" for an added word, "=" for a skipped one and "-" for a rejected one.
+func printImportReport(report *dictionary.ImportReport, dryRun bool) {
+	marks := map[string]string{
+		dictionary.ImportAdded:    "+",
+		dictionary.ImportSkipped:  "=",
+		dictionary.ImportRejected: "-",
+	}
+
+	var b strings.Builder
+	for _, item := range report.Items {
+		_, _ = fmt.Fprintf(&b, "%s %-12s line %d", marks[item.Status], item.Word, item.Line)
+		if item.Word != item.Raw {
+			_, _ = fmt.Fprintf(&b, ", normalized from «%s»", item.Raw)
+		}
+		if item.Reason != "" {
+			_, _ = fmt.Fprintf(&b, ", %s", item.Reason)
+		}
+		b.WriteString("\n")
+	}
+	_, _ = fmt.Fprintf(
+		&b,
+		"\nadded: %d, skipped: %d, rejected: %d\n",
+		report.Count(dictionary.ImportAdded),
+		report.Count(dictionary.ImportSkipped),
+		report.Count(dictionary.ImportRejected),
+	)
+	if dryRun {
+		b.WriteString("dry run: nothing was saved\n")
+	}
+	_, _ = fmt.Fprint(os.Stdout, b.String())
+}
+
+func listSingleSourceWords(setup *config.Setup, logger *slog.Logger, args []string) error {
+	flags := flag.NewFlagSet("words single-source", flag.ContinueOnError)
+	source := flags.String("source", "", "list only the words of this source")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	db := postgres.New(setup.Env.PgDSN, logger)
+	defer func() {
+		if err := db.Close(); err != nil {
+			slogext.Error(logger, err)
+		}
+	}()
+
+	sources, err := dictionary.NewDictionaryUseCase(repo.NewDictionaryRepository(db)).FindSingleSourceWords(*source)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	for _, wordSource := range sources {
+		tier := "guess"
+		if wordSource.Word.IsAnswer {
+			tier = "answer"
+		}
+		_, _ = fmt.Fprintf(
+			&b,
+			"%-10s %-6s %-24s %s\n",
+			wordSource.Word.Word,
+			tier,
+			wordSource.Source,
+			wordSource.FirstSeenAt.Format(time.DateOnly),
+		)
+	}
+	_, _ = fmt.Fprintf(&b, "\n%d words\n", len(sources))
+	_, _ = fmt.Fprint(os.Stdout, b.String())
+
+	return nil
+}
+
+// rotateKeys generates a new signing key in the key directory. The first rotation also keeps the public key
+// of the environment, so the tokens signed with it stay valid until they expire.
+func rotateKeys(setup *config.Setup, logger *slog.Logger, args []string) error {
+	flags := flag.NewFlagSet("keys rotate", flag.ContinueOnError)
+	retain := flags.Duration("retain", 48*time.Hour, "keep the retired keys verifying tokens this long, longer than any token lives")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	dir := setup.Config.Auth.KeyDir
+	if dir == "" {
+		return errors.New("key_dir is not configured")
+	}
+
+	current, err := serviceJwt.LoadKeyDir(dir)
+	if errors.Is(err, serviceJwt.ErrNoActiveKey) && setup.Env.ES256PrivateKey != "" {
+		current, err = serviceJwt.NewKeySetFromPem(setup.Env.ES256PrivateKey, setup.Env.ES256PublicKey)
+	} else if errors.Is(err, serviceJwt.ErrNoActiveKey) {
+		current, err = nil, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	key, err := serviceJwt.RotateKeys(dir, current, *retain)
+	if err != nil {
+		return err
+	}
+	logger.Info("Keys:Rotate", "kid", key.Id, "dir", dir)
+
+	return nil
+}
+
+// setUserRole bootstraps the first admins, who then manage the roles through the admin API.
+func setUserRole(setup *config.Setup, logger *slog.Logger, args []string) error {
+	if len(args) != 2 {
+		return errors.New("an email and a role are expected")
+	}
+
+	db := postgres.New(setup.Env.PgDSN, logger)
+	defer func() {
+		if err := db.Close(); err != nil {
+			slogext.Error(logger, err)
+		}
+	}()
+
+	authRepo := repo.NewAuthRepository(db)
+	user, err := authRepo.FindBy(args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	if err = user.SetRole(args[1]); err != nil {
+		return err
+	}
+	if err = authRepo.UpdateRole(user); err != nil {
+		return err
+	}
+	logger.Info("Users:Role", "user", user.Id, "role", user.Role)
+
+	return nil
+}
+
+// runMockProvider serves the "mock" provider of the dev config until interrupted.
+func runMockProvider(_ *config.Setup, logger *slog.Logger, args []string) error {
+	flags := flag.NewFlagSet("oidc mock", flag.ContinueOnError)
+	addr := flags.String("addr", "localhost:9090", "address to listen on")
+	issuer := flags.String("issuer", "http://localhost:9090", "issuer URL the provider is reachable at")
+	email := flags.String("email", "player@wordka.localhost", "email of the user logged in without a login_hint")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	server, err := mock.NewServer(*issuer, mock.User{
+		Subject:       *email,
+		Email:         *email,
+		EmailVerified: true,
+		Name:          strings.Split(*email, "@")[0],
+	})
+	if err != nil {
+		return err
+	}
+	logger.Info("Oidc:Mock", "addr", *addr, "issuer", *issuer)
+
+	return http.ListenAndServe(*addr, server)
+}
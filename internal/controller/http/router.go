@@ -3,6 +3,8 @@ package http
 import (
 	"github.com/Markard/wordka/config"
 	"github.com/Markard/wordka/internal/controller/http/v1"
+	"github.com/Markard/wordka/internal/controller/http/wellknown"
+	"github.com/Markard/wordka/internal/infra/eventbus"
 	projectMiddleware "github.com/Markard/wordka/internal/infra/middleware"
 	"github.com/Markard/wordka/internal/usecase"
 	"github.com/Markard/wordka/pkg/http/validator"
@@ -20,14 +22,24 @@ func SetupRouter(
 	val validator.ProjectValidator,
 	middlewares *projectMiddleware.Middlewares,
 	useCases *usecase.UseCases,
+	bus eventbus.Bus,
 ) {
+	if setup.Config.RateLimit.TrustProxy {
+		router.Use(middleware.RealIP)
+	}
 	router.Use(slogchi.New(slog.Default()))
 	router.Use(middleware.Recoverer)
-	router.Use(middleware.Timeout(setup.Config.HttpServer.Timeout))
 
-	router.Get("/robots.txt", robotsTxt)
-	router.Get("/health", healthCheck)
-	router.Mount("/v1", v1.CreateRouter(val, middlewares, useCases))
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(setup.Config.HttpServer.Timeout))
+
+		r.Get("/robots.txt", robotsTxt)
+		r.Get("/health", healthCheck)
+		r.Mount("/.well-known", wellknown.CreateRouter(useCases.AuthUseCase))
+		r.Mount("/v1", v1.CreateRouter(val, middlewares, useCases))
+		r.Mount("/s", v1.CreatePublicShareRouter(useCases))
+	})
+	router.Mount("/v1/events", v1.CreateStreamRouter(middlewares, bus))
 }
 
 func robotsTxt(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,34 @@
+package adminuser
+
+import (
+	"github.com/Markard/wordka/internal/entity"
+	"time"
+)
+
+type Response struct {
+	Id           int64      `json:"id"`
+	Name         string     `json:"name"`
+	Email        string     `json:"email"`
+	Role         string     `json:"role"`
+	BannedAt     *time.Time `json:"banned_at"`
+	BanReason    string     `json:"ban_reason,omitempty"`
+	StatsResetAt *time.Time `json:"stats_reset_at"`
+}
+
+func NewResponse(user *entity.User) *Response {
+	resp := &Response{
+		Id:        user.Id,
+		Name:      user.Name,
+		Email:     user.Email,
+		Role:      user.Role,
+		BanReason: user.BanReason,
+	}
+	if user.IsBanned() {
+		resp.BannedAt = &user.BannedAt.Time
+	}
+	if !user.StatsResetAt.IsZero() {
+		resp.StatsResetAt = &user.StatsResetAt.Time
+	}
+
+	return resp
+}
@@ -0,0 +1,28 @@
+package adminword
+
+import (
+	"github.com/Markard/wordka/internal/entity"
+	"time"
+)
+
+type Response struct {
+	Id          int       `json:"id"`
+	Word        string    `json:"word"`
+	Length      int       `json:"length"`
+	IsEnabled   bool      `json:"is_enabled"`
+	IsAnswer    bool      `json:"is_answer"`
+	IsOffensive bool      `json:"is_offensive"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func NewResponse(word *entity.Word) *Response {
+	return &Response{
+		Id:          word.Id,
+		Word:        word.Word,
+		Length:      word.Length,
+		IsEnabled:   word.IsEnabled,
+		IsAnswer:    word.IsAnswer,
+		IsOffensive: word.IsOffensive,
+		CreatedAt:   word.CreatedAt,
+	}
+}
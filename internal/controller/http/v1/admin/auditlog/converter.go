@@ -0,0 +1,41 @@
+package auditlog
+
+import (
+	"github.com/Markard/wordka/pkg/http/response"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"net/http"
+	"strconv"
+)
+
+type Converter struct {
+	validator validator.ProjectValidator
+}
+
+func NewConverter(validator validator.ProjectValidator) *Converter {
+	return &Converter{validator: validator}
+}
+
+func (c *Converter) ValidateAndApply(r *http.Request) (*Request, *response.ValidationError) {
+	query := r.URL.Query()
+	auditLogReq := &Request{Limit: defaultLimit}
+	if before := query.Get("before"); before != "" {
+		b, err := strconv.ParseInt(before, 10, 64)
+		if err != nil {
+			return nil, response.NewValidationError().AddFieldError("before", "The 'before' field must be a number.")
+		}
+		auditLogReq.Before = b
+	}
+	if limit := query.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return nil, response.NewValidationError().AddFieldError("limit", "The 'limit' field must be a number.")
+		}
+		auditLogReq.Limit = l
+	}
+
+	if errVal := c.validator.Struct(auditLogReq); errVal != nil {
+		return nil, errVal
+	}
+
+	return auditLogReq, nil
+}
@@ -0,0 +1,8 @@
+package auditlog
+
+const defaultLimit = 50
+
+type Request struct {
+	Before int64 `validate:"min=0"`
+	Limit  int   `validate:"min=1,max=200"`
+}
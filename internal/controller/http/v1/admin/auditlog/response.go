@@ -0,0 +1,47 @@
+package auditlog
+
+import (
+	"github.com/Markard/wordka/internal/entity"
+	"time"
+)
+
+type Actor struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type Entry struct {
+	Id         int64          `json:"id"`
+	Actor      *Actor         `json:"actor"`
+	Action     string         `json:"action"`
+	TargetType string         `json:"target_type"`
+	Target     string         `json:"target"`
+	Details    map[string]any `json:"details"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+type Response struct {
+	Entries []*Entry `json:"entries"`
+	// NextBefore pages further back in the trail, it is omitted on the last page.
+	NextBefore int64 `json:"next_before,omitempty"`
+}
+
+func NewResponse(entries []*entity.AuditEntry, limit int) *Response {
+	resp := &Response{Entries: make([]*Entry, 0, len(entries))}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, &Entry{
+			Id:         e.Id,
+			Actor:      &Actor{Id: e.Actor.Id, Name: e.Actor.Name},
+			Action:     e.Action,
+			TargetType: e.TargetType,
+			Target:     e.Target,
+			Details:    e.Details,
+			CreatedAt:  e.CreatedAt,
+		})
+	}
+	if len(entries) == limit {
+		resp.NextBefore = entries[len(entries)-1].Id
+	}
+
+	return resp
+}
@@ -0,0 +1,5 @@
+package ban
+
+type Request struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
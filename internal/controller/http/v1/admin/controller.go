@@ -0,0 +1,321 @@
+package admin
+
+import (
+	"errors"
+	"github.com/Markard/wordka/internal/controller/http/v1/admin/adminuser"
+	"github.com/Markard/wordka/internal/controller/http/v1/admin/adminword"
+	"github.com/Markard/wordka/internal/controller/http/v1/admin/auditlog"
+	"github.com/Markard/wordka/internal/controller/http/v1/admin/ban"
+	"github.com/Markard/wordka/internal/controller/http/v1/admin/newword"
+	"github.com/Markard/wordka/internal/controller/http/v1/admin/puzzlelist"
+	"github.com/Markard/wordka/internal/controller/http/v1/admin/reorder"
+	"github.com/Markard/wordka/internal/controller/http/v1/admin/schedule"
+	"github.com/Markard/wordka/internal/controller/http/v1/admin/userrole"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/infra/middleware/jwt"
+	"github.com/Markard/wordka/internal/usecase/admin"
+	"github.com/Markard/wordka/pkg/http/response"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"github.com/Markard/wordka/pkg/slogext"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type Controller struct {
+	useCase   *admin.UseCase
+	validator validator.ProjectValidator
+}
+
+func NewController(useCase *admin.UseCase, validator validator.ProjectValidator) *Controller {
+	return &Controller{useCase: useCase, validator: validator}
+}
+
+func (c *Controller) BanUser(w http.ResponseWriter, r *http.Request) {
+	userId, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.ErrNotFound(w, admin.ErrUserNotFound)
+		return
+	}
+
+	converter := ban.NewConverter(c.validator)
+	banRequest, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	user, err := c.useCase.Ban(currentUser, userId, banRequest.Reason)
+	if err != nil {
+		c.errUser(w, err)
+		return
+	}
+
+	resp := adminuser.NewResponse(user)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) UnbanUser(w http.ResponseWriter, r *http.Request) {
+	userId, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.ErrNotFound(w, admin.ErrUserNotFound)
+		return
+	}
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	user, err := c.useCase.Unban(currentUser, userId)
+	if err != nil {
+		c.errUser(w, err)
+		return
+	}
+
+	resp := adminuser.NewResponse(user)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) ResetStats(w http.ResponseWriter, r *http.Request) {
+	userId, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.ErrNotFound(w, admin.ErrUserNotFound)
+		return
+	}
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	user, err := c.useCase.ResetStats(currentUser, userId)
+	if err != nil {
+		c.errUser(w, err)
+		return
+	}
+
+	resp := adminuser.NewResponse(user)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) SetRole(w http.ResponseWriter, r *http.Request) {
+	userId, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.ErrNotFound(w, admin.ErrUserNotFound)
+		return
+	}
+
+	converter := userrole.NewConverter(c.validator)
+	roleRequest, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	user, err := c.useCase.SetRole(currentUser, userId, roleRequest.Role)
+	if err != nil {
+		c.errUser(w, err)
+		return
+	}
+
+	resp := adminuser.NewResponse(user)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) AddWord(w http.ResponseWriter, r *http.Request) {
+	converter := newword.NewConverter(c.validator)
+	wordRequest, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	word, err := c.useCase.AddWord(currentUser, wordRequest.Word, wordRequest.IsAnswer)
+	if err != nil {
+		if errors.Is(err, admin.ErrWordAlreadyExists) {
+			response.ErrConflict(w, err)
+		} else {
+			c.errWord(w, err)
+		}
+		return
+	}
+
+	resp := adminword.NewResponse(word)
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) DisableWord(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	raw, _ := url.PathUnescape(chi.URLParam(r, "word"))
+	word, err := c.useCase.DisableWord(currentUser, raw)
+	if err != nil {
+		c.errWord(w, err)
+		return
+	}
+
+	resp := adminword.NewResponse(word)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) MarkAnswer(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	raw, _ := url.PathUnescape(chi.URLParam(r, "word"))
+	word, err := c.useCase.MarkAnswer(currentUser, raw)
+	if err != nil {
+		c.errWord(w, err)
+		return
+	}
+
+	resp := adminword.NewResponse(word)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) GetDailyPuzzles(w http.ResponseWriter, r *http.Request) {
+	converter := puzzlelist.NewConverter(c.validator)
+	puzzleListRequest, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	puzzles, err := c.useCase.FindDailyPuzzles(puzzleListRequest.Limit)
+	if err != nil {
+		response.ErrInternalServer(w)
+		slogext.Error(slog.Default(), err)
+		return
+	}
+
+	resp := puzzlelist.NewResponse(puzzles)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) ScheduleDailyPuzzle(w http.ResponseWriter, r *http.Request) {
+	date, err := c.useCase.ParseDate(chi.URLParam(r, "date"))
+	if err != nil {
+		response.NewValidationError().AddFieldError("date", "The date must be in the YYYY-MM-DD format").ErrValidation(w)
+		return
+	}
+
+	converter := schedule.NewConverter(c.validator)
+	scheduleRequest, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	puzzle, err := c.useCase.ScheduleDailyPuzzle(currentUser, date, scheduleRequest.Word)
+	if err != nil {
+		if errors.Is(err, admin.ErrWordNotPlayable) || errors.Is(err, admin.ErrDailyPuzzleWrongWordLength) {
+			response.NewValidationError().AddFieldError("word", err.Error()).ErrValidation(w)
+		} else if errors.Is(err, admin.ErrDailyPuzzleAlreadyExists) {
+			response.ErrConflict(w, err)
+		} else {
+			c.errDailyPuzzle(w, err)
+		}
+		return
+	}
+
+	resp := puzzlelist.NewDailyPuzzle(puzzle)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) ReorderDailyPuzzles(w http.ResponseWriter, r *http.Request) {
+	converter := reorder.NewConverter(c.validator)
+	reorderRequest, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	date, _ := c.useCase.ParseDate(reorderRequest.Date)
+	otherDate, _ := c.useCase.ParseDate(reorderRequest.OtherDate)
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	puzzles, err := c.useCase.ReorderDailyPuzzles(currentUser, date, otherDate)
+	if err != nil {
+		if errors.Is(err, admin.ErrDailyPuzzleSameDates) {
+			response.NewValidationError().AddFieldError("other_date", err.Error()).ErrValidation(w)
+		} else {
+			c.errDailyPuzzle(w, err)
+		}
+		return
+	}
+
+	resp := puzzlelist.NewResponse(puzzles)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	converter := auditlog.NewConverter(c.validator)
+	auditLogRequest, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	entries, err := c.useCase.FindAuditEntries(auditLogRequest.Before, auditLogRequest.Limit)
+	if err != nil {
+		response.ErrInternalServer(w)
+		slogext.Error(slog.Default(), err)
+		return
+	}
+
+	resp := auditlog.NewResponse(entries, auditLogRequest.Limit)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) errUser(w http.ResponseWriter, err error) {
+	if errors.Is(err, admin.ErrUserNotFound) {
+		response.ErrNotFound(w, err)
+	} else if errors.Is(err, admin.ErrInsufficientRole) {
+		response.ErrHttpError(w, http.StatusForbidden, "Users of the same or a higher role cannot be managed.")
+	} else if errors.Is(err, entity.ErrUserAlreadyBanned) || errors.Is(err, entity.ErrUserNotBanned) {
+		response.ErrConflict(w, err)
+	} else if errors.Is(err, entity.ErrUnknownRole) {
+		response.NewValidationError().AddFieldError("role", err.Error()).ErrValidation(w)
+	} else {
+		response.ErrInternalServer(w)
+		slogext.Error(slog.Default(), err)
+	}
+}
+
+func (c *Controller) errWord(w http.ResponseWriter, err error) {
+	if errors.Is(err, admin.ErrWordNotFound) {
+		response.ErrNotFound(w, err)
+	} else if errors.Is(err, entity.ErrNotRussianWord) ||
+		errors.Is(err, entity.ErrHyphenatedWord) ||
+		errors.Is(err, entity.ErrUnsupportedLength) {
+		response.NewValidationError().AddFieldError("word", err.Error()).ErrValidation(w)
+	} else if errors.Is(err, entity.ErrOffensiveAnswer) || errors.Is(err, entity.ErrDisabledAnswer) {
+		response.ErrConflict(w, err)
+	} else {
+		response.ErrInternalServer(w)
+		slogext.Error(slog.Default(), err)
+	}
+}
+
+func (c *Controller) errDailyPuzzle(w http.ResponseWriter, err error) {
+	if errors.Is(err, admin.ErrDailyPuzzleNotFound) || errors.Is(err, admin.ErrWordNotFound) {
+		response.ErrNotFound(w, err)
+	} else if errors.Is(err, admin.ErrDailyPuzzleInPast) || errors.Is(err, admin.ErrDailyPuzzleAlreadyPlayed) {
+		response.ErrConflict(w, err)
+	} else if errors.Is(err, entity.ErrNotRussianWord) ||
+		errors.Is(err, entity.ErrHyphenatedWord) ||
+		errors.Is(err, entity.ErrUnsupportedLength) {
+		response.NewValidationError().AddFieldError("word", err.Error()).ErrValidation(w)
+	} else {
+		response.ErrInternalServer(w)
+		slogext.Error(slog.Default(), err)
+	}
+}
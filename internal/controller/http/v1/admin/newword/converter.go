@@ -0,0 +1,31 @@
+package newword
+
+import (
+	"encoding/json"
+	"github.com/Markard/wordka/pkg/http/response"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"net/http"
+)
+
+type Converter struct {
+	validator validator.ProjectValidator
+}
+
+func NewConverter(validator validator.ProjectValidator) *Converter {
+	return &Converter{validator: validator}
+}
+
+func (c *Converter) ValidateAndApply(r *http.Request) (*Request, *response.ValidationError) {
+	wordReq := &Request{}
+
+	err := json.NewDecoder(r.Body).Decode(wordReq)
+	if err != nil {
+		return nil, response.NewValidationError().AddFieldError("body", err.Error())
+	}
+
+	if errVal := c.validator.Struct(wordReq); errVal != nil {
+		return nil, errVal
+	}
+
+	return wordReq, nil
+}
@@ -0,0 +1,6 @@
+package newword
+
+type Request struct {
+	Word     string `json:"word" validate:"required,max=32"`
+	IsAnswer bool   `json:"is_answer"`
+}
@@ -0,0 +1,33 @@
+package puzzlelist
+
+import (
+	"github.com/Markard/wordka/pkg/http/response"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"net/http"
+	"strconv"
+)
+
+type Converter struct {
+	validator validator.ProjectValidator
+}
+
+func NewConverter(validator validator.ProjectValidator) *Converter {
+	return &Converter{validator: validator}
+}
+
+func (c *Converter) ValidateAndApply(r *http.Request) (*Request, *response.ValidationError) {
+	puzzleListReq := &Request{Limit: defaultLimit}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return nil, response.NewValidationError().AddFieldError("limit", "The 'limit' field must be a number.")
+		}
+		puzzleListReq.Limit = l
+	}
+
+	if errVal := c.validator.Struct(puzzleListReq); errVal != nil {
+		return nil, errVal
+	}
+
+	return puzzleListReq, nil
+}
@@ -0,0 +1,7 @@
+package puzzlelist
+
+const defaultLimit = 30
+
+type Request struct {
+	Limit int `validate:"min=1,max=366"`
+}
@@ -0,0 +1,33 @@
+package puzzlelist
+
+import (
+	"github.com/Markard/wordka/internal/entity"
+	"time"
+)
+
+type DailyPuzzle struct {
+	Id   int64  `json:"id"`
+	Date string `json:"date"`
+	Word string `json:"word"`
+}
+
+type Response struct {
+	DailyPuzzles []*DailyPuzzle `json:"daily_puzzles"`
+}
+
+func NewResponse(puzzles []*entity.DailyPuzzle) *Response {
+	resp := &Response{DailyPuzzles: make([]*DailyPuzzle, 0, len(puzzles))}
+	for _, puzzle := range puzzles {
+		resp.DailyPuzzles = append(resp.DailyPuzzles, NewDailyPuzzle(puzzle))
+	}
+
+	return resp
+}
+
+func NewDailyPuzzle(puzzle *entity.DailyPuzzle) *DailyPuzzle {
+	return &DailyPuzzle{
+		Id:   puzzle.Id,
+		Date: puzzle.Date.Format(time.DateOnly),
+		Word: puzzle.Word.Word,
+	}
+}
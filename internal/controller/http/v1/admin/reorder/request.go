@@ -0,0 +1,6 @@
+package reorder
+
+type Request struct {
+	Date      string `json:"date" validate:"required,datetime=2006-01-02"`
+	OtherDate string `json:"other_date" validate:"required,datetime=2006-01-02"`
+}
@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/infra/middleware/jwt"
+	"github.com/Markard/wordka/internal/usecase/admin"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"github.com/go-chi/chi/v5"
+)
+
+func CreateRouter(val validator.ProjectValidator, useCase *admin.UseCase) *chi.Mux {
+	r := chi.NewRouter()
+	c := NewController(useCase, val)
+
+	r.Group(func(r chi.Router) {
+		r.Use(jwt.RequireRole(entity.RoleModerator))
+
+		r.Post("/users/{id:[0-9]+}/ban", c.BanUser)
+		r.Delete("/users/{id:[0-9]+}/ban", c.UnbanUser)
+		r.Post("/words", c.AddWord)
+		r.Post("/words/{word}/disable", c.DisableWord)
+		r.Post("/words/{word}/answer", c.MarkAnswer)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(jwt.RequireRole(entity.RoleAdmin))
+
+		r.Post("/users/{id:[0-9]+}/reset-stats", c.ResetStats)
+		r.Put("/users/{id:[0-9]+}/role", c.SetRole)
+		r.Get("/daily-puzzles", c.GetDailyPuzzles)
+		r.Put("/daily-puzzles/{date}", c.ScheduleDailyPuzzle)
+		r.Post("/daily-puzzles/reorder", c.ReorderDailyPuzzles)
+		r.Get("/audit", c.GetAuditLog)
+	})
+
+	return r
+}
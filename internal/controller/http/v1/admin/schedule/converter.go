@@ -0,0 +1,31 @@
+package schedule
+
+import (
+	"encoding/json"
+	"github.com/Markard/wordka/pkg/http/response"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"net/http"
+)
+
+type Converter struct {
+	validator validator.ProjectValidator
+}
+
+func NewConverter(validator validator.ProjectValidator) *Converter {
+	return &Converter{validator: validator}
+}
+
+func (c *Converter) ValidateAndApply(r *http.Request) (*Request, *response.ValidationError) {
+	scheduleReq := &Request{}
+
+	err := json.NewDecoder(r.Body).Decode(scheduleReq)
+	if err != nil {
+		return nil, response.NewValidationError().AddFieldError("body", err.Error())
+	}
+
+	if errVal := c.validator.Struct(scheduleReq); errVal != nil {
+		return nil, errVal
+	}
+
+	return scheduleReq, nil
+}
@@ -0,0 +1,5 @@
+package schedule
+
+type Request struct {
+	Word string `json:"word" validate:"required,max=32"`
+}
@@ -0,0 +1,5 @@
+package userrole
+
+type Request struct {
+	Role string `json:"role" validate:"required,oneof=player moderator admin"`
+}
@@ -2,15 +2,26 @@ package auth
 
 import (
 	"errors"
+	"github.com/Markard/wordka/internal/controller/http/v1/auth/externallogin"
+	"github.com/Markard/wordka/internal/controller/http/v1/auth/externalstart"
+	"github.com/Markard/wordka/internal/controller/http/v1/auth/forgotpassword"
 	"github.com/Markard/wordka/internal/controller/http/v1/auth/login"
+	"github.com/Markard/wordka/internal/controller/http/v1/auth/refresh"
 	"github.com/Markard/wordka/internal/controller/http/v1/auth/registration"
+	"github.com/Markard/wordka/internal/controller/http/v1/auth/resetpassword"
+	"github.com/Markard/wordka/internal/controller/http/v1/auth/verification"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/infra/middleware/jwt"
+	serviceJwt "github.com/Markard/wordka/internal/infra/service/jwt"
 	"github.com/Markard/wordka/internal/usecase/auth"
 	"github.com/Markard/wordka/pkg/http/response"
 	"github.com/Markard/wordka/pkg/http/validator"
 	"github.com/Markard/wordka/pkg/slogext"
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"log/slog"
 	"net/http"
+	"strings"
 )
 
 type Controller struct {
@@ -31,14 +42,22 @@ func (c *Controller) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	user, err := c.useCase.Register(regRequest.Name, regRequest.Email, regRequest.Password)
+	if c.useCase.IsRegistrationUniform() {
+		c.registerUniformly(w, err)
+		return
+	}
 	if err != nil {
 		if errors.Is(err, auth.ErrUserAlreadyExists) {
 			response.ErrConflict(w, err)
 			return
+		} else if errors.Is(err, auth.ErrVerificationNotSent) {
+			// The user is registered anyway and can ask to resend the email.
+			slogext.Error(slog.Default(), err)
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
 		}
-		response.ErrInternalServer(w)
-		slogext.Error(slog.Default(), err)
-		return
 	}
 
 	resp := registration.NewResponse(user)
@@ -46,6 +65,22 @@ func (c *Controller) Register(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, resp)
 }
 
+// registerUniformly answers the same whether the email was free or taken, the outcome is emailed instead.
+func (c *Controller) registerUniformly(w http.ResponseWriter, err error) {
+	if err != nil {
+		if errors.Is(err, auth.ErrVerificationNotSent) || errors.Is(err, auth.ErrNoticeNotSent) {
+			// Neither email is essential: the verification can be resent after logging in.
+			slogext.Error(slog.Default(), err)
+		} else if !errors.Is(err, auth.ErrUserAlreadyExists) {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
 func (c *Controller) Login(w http.ResponseWriter, r *http.Request) {
 	converter := login.NewConverter(c.validator)
 	loginRequest, valErr := converter.ValidateAndApply(r)
@@ -54,10 +89,234 @@ func (c *Controller) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokenString, err := c.useCase.Login(loginRequest.Email, loginRequest.Password)
+	tokens, err := c.useCase.Login(loginRequest.Email, loginRequest.Password)
 	if err != nil {
 		if errors.Is(err, auth.ErrUserNotFound) {
 			response.ErrHttpError(w, http.StatusUnauthorized, "The credentials provided are incorrect.")
+		} else if errors.Is(err, auth.ErrUserBanned) {
+			response.ErrHttpError(w, http.StatusForbidden, "The account is banned.")
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+		}
+		return
+	}
+
+	resp := login.NewResponse(tokens)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) Refresh(w http.ResponseWriter, r *http.Request) {
+	converter := refresh.NewConverter(c.validator)
+	refreshRequest, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	tokens, err := c.useCase.Refresh(refreshRequest.RefreshToken)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidRefreshToken) || errors.Is(err, auth.ErrRefreshTokenReused) {
+			response.ErrHttpError(w, http.StatusUnauthorized, err.Error())
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+		}
+		return
+	}
+
+	resp := login.NewResponse(tokens)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) Logout(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	token, _ := r.Context().Value(jwt.TokenCtxKey).(*serviceJwt.Token)
+	if err := c.useCase.Logout(currentUser, token); err != nil {
+		response.ErrInternalServer(w)
+		slogext.Error(slog.Default(), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Controller) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	token, _ := r.Context().Value(jwt.TokenCtxKey).(*serviceJwt.Token)
+	if err := c.useCase.LogoutAll(currentUser, token); err != nil {
+		response.ErrInternalServer(w)
+		slogext.Error(slog.Default(), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Controller) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	converter := verification.NewConverter(c.validator)
+	verificationRequest, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	user, err := c.useCase.VerifyEmail(verificationRequest.Token)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidVerificationToken) {
+			response.
+				NewValidationError().
+				AddFieldError("token", "The verification link is invalid or has expired").
+				ErrValidation(w)
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+		}
+		return
+	}
+
+	resp := registration.NewResponse(user)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	err := c.useCase.ResendVerification(currentUser)
+	if err != nil {
+		var throttledErr *auth.ResendThrottledError
+		if errors.Is(err, auth.ErrEmailAlreadyVerified) {
+			response.ErrConflict(w, err)
+		} else if errors.As(err, &throttledErr) {
+			response.ErrTooManyRequests(w, throttledErr.RetryAfter, err.Error())
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (c *Controller) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	converter := forgotpassword.NewConverter(c.validator)
+	forgotRequest, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	if err := c.useCase.ForgotPassword(forgotRequest.Email); err != nil {
+		response.ErrInternalServer(w)
+		slogext.Error(slog.Default(), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (c *Controller) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	converter := resetpassword.NewConverter(c.validator)
+	resetRequest, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	if err := c.useCase.ResetPassword(resetRequest.Token, resetRequest.Password); err != nil {
+		if errors.Is(err, auth.ErrInvalidResetToken) {
+			response.
+				NewValidationError().
+				AddFieldError("token", "The password reset link is invalid, expired or was already used").
+				ErrValidation(w)
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// externalLoginCookie keeps the state of an external login in the browser which started it.
+const externalLoginCookie = "external_login"
+
+func (c *Controller) StartExternalLogin(w http.ResponseWriter, r *http.Request) {
+	authorizationUrl, state, err := c.useCase.StartExternalLogin(r.Context(), chi.URLParam(r, "provider"))
+	if err != nil {
+		if errors.Is(err, auth.ErrUnknownProvider) {
+			response.ErrNotFound(w, err)
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+		}
+		return
+	}
+
+	// The cookie is sent only to the callback of the provider, the login lives as long as the browser session at most.
+	http.SetCookie(w, &http.Cookie{
+		Name:     externalLoginCookie,
+		Value:    state,
+		Path:     strings.TrimSuffix(r.URL.Path, "/start"),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	resp := externalstart.NewResponse(authorizationUrl)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) CompleteExternalLogin(w http.ResponseWriter, r *http.Request) {
+	converter := externallogin.NewConverter(c.validator)
+	externalRequest, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	var browserState string
+	if cookie, err := r.Cookie(externalLoginCookie); err == nil {
+		browserState = cookie.Value
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     externalLoginCookie,
+		Path:     strings.TrimSuffix(r.URL.Path, "/callback"),
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	tokens, err := c.useCase.CompleteExternalLogin(
+		r.Context(),
+		chi.URLParam(r, "provider"),
+		externalRequest.Code,
+		externalRequest.State,
+		browserState,
+	)
+	if err != nil {
+		if errors.Is(err, auth.ErrUnknownProvider) {
+			response.ErrNotFound(w, err)
+		} else if errors.Is(err, auth.ErrInvalidLoginState) {
+			response.
+				NewValidationError().
+				AddFieldError("state", "The login is invalid, expired or was already completed").
+				ErrValidation(w)
+		} else if errors.Is(err, auth.ErrExternalLoginFailed) {
+			response.ErrHttpError(w, http.StatusUnauthorized, "The provider did not confirm the login.")
+			slog.Default().Warn("ExternalLogin: Login not confirmed", "err", err)
+		} else if errors.Is(err, auth.ErrNoProviderEmail) {
+			response.ErrHttpError(w, http.StatusUnprocessableEntity, "The provider did not share the email, allow it to.")
+		} else if errors.Is(err, auth.ErrProviderEmailNotVerified) || errors.Is(err, auth.ErrUserAlreadyExists) {
+			response.ErrConflict(w, err)
+		} else if errors.Is(err, auth.ErrUserBanned) {
+			response.ErrHttpError(w, http.StatusForbidden, "The account is banned.")
 		} else {
 			response.ErrInternalServer(w)
 			slogext.Error(slog.Default(), err)
@@ -65,7 +324,7 @@ func (c *Controller) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := login.NewResponse(tokenString)
+	resp := login.NewResponse(tokens)
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, resp)
 }
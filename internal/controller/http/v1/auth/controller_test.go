@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Markard/wordka/internal/usecase/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegisterUniformlyHidesTakenEmails(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "registered", err: nil, want: http.StatusAccepted},
+		{name: "email taken", err: auth.ErrUserAlreadyExists, want: http.StatusAccepted},
+		{name: "notice not sent", err: fmt.Errorf("%w: %w", auth.ErrNoticeNotSent, errors.New("smtp down")), want: http.StatusAccepted},
+		{name: "verification not sent", err: fmt.Errorf("%w: %w", auth.ErrVerificationNotSent, errors.New("smtp down")), want: http.StatusAccepted},
+		{name: "failure", err: errors.New("db down"), want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			(&Controller{}).registerUniformly(w, tt.err)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
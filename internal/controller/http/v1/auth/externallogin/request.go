@@ -0,0 +1,7 @@
+package externallogin
+
+// Request carries the parameters the provider has redirected the user back with.
+type Request struct {
+	Code  string `json:"code" validate:"required"`
+	State string `json:"state" validate:"required"`
+}
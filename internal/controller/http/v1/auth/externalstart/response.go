@@ -0,0 +1,9 @@
+package externalstart
+
+type Response struct {
+	AuthorizationUrl string `json:"authorization_url"`
+}
+
+func NewResponse(authorizationUrl string) *Response {
+	return &Response{AuthorizationUrl: authorizationUrl}
+}
@@ -0,0 +1,5 @@
+package forgotpassword
+
+type Request struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+}
@@ -1,9 +1,22 @@
 package login
 
+import (
+	"github.com/Markard/wordka/internal/usecase/auth"
+	"time"
+)
+
 type Response struct {
-	Token string `json:"token"`
+	Token                 string    `json:"token"`
+	TokenExpiresAt        time.Time `json:"token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
 }
 
-func NewResponse(tokenString string) *Response {
-	return &Response{tokenString}
+func NewResponse(tokens *auth.Tokens) *Response {
+	return &Response{
+		Token:                 tokens.AccessToken,
+		TokenExpiresAt:        tokens.AccessTokenExpiresAt,
+		RefreshToken:          tokens.RefreshToken,
+		RefreshTokenExpiresAt: tokens.RefreshTokenExpiresAt,
+	}
 }
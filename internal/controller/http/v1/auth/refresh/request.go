@@ -0,0 +1,5 @@
+package refresh
+
+type Request struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
@@ -0,0 +1,6 @@
+package resetpassword
+
+type Request struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=16,validate_password"`
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"github.com/Markard/wordka/internal/infra/middleware"
 	"github.com/Markard/wordka/internal/usecase/auth"
 	"github.com/Markard/wordka/pkg/http/validator"
 	"github.com/go-chi/chi/v5"
@@ -9,12 +10,30 @@ import (
 func CreateRouter(
 	val validator.ProjectValidator,
 	useCase *auth.UseCase,
+	middlewares *middleware.Middlewares,
 ) *chi.Mux {
 	r := chi.NewRouter()
 	c := NewController(useCase, val)
 
-	r.Post("/register", c.Register)
-	r.Post("/login", c.Login)
+	r.Group(func(r chi.Router) {
+		r.Use(middlewares.IpRateLimit)
+
+		r.Post("/token/refresh", c.Refresh)
+		r.Post("/email/verify", c.VerifyEmail)
+		r.Post("/password/reset", c.ResetPassword)
+		r.With(middlewares.EmailRateLimit).Post("/register", c.Register)
+		r.With(middlewares.EmailRateLimit).Post("/login", c.Login)
+		r.With(middlewares.EmailRateLimit).Post("/password/forgot", c.ForgotPassword)
+		r.Post("/oauth/{provider}/start", c.StartExternalLogin)
+		r.Post("/oauth/{provider}/callback", c.CompleteExternalLogin)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(middlewares.JwtAuthenticator)
+
+		r.Post("/logout", c.Logout)
+		r.Post("/logout-all", c.LogoutAll)
+		r.Post("/email/resend", c.ResendVerification)
+	})
 
 	return r
 }
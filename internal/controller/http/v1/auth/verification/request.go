@@ -0,0 +1,5 @@
+package verification
+
+type Request struct {
+	Token string `json:"token" validate:"required"`
+}
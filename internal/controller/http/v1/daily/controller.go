@@ -0,0 +1,151 @@
+package daily
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Markard/wordka/internal/controller/http/v1/daily/dailygame"
+	"github.com/Markard/wordka/internal/controller/http/v1/game/guess"
+	"github.com/Markard/wordka/internal/controller/http/v1/game/newgame"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/infra/middleware/jwt"
+	"github.com/Markard/wordka/internal/usecase/game"
+	"github.com/Markard/wordka/pkg/http/response"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"github.com/Markard/wordka/pkg/slogext"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Controller struct {
+	useCase   *game.UseCase
+	validator validator.ProjectValidator
+}
+
+func NewController(useCase *game.UseCase, validator validator.ProjectValidator) *Controller {
+	return &Controller{useCase: useCase, validator: validator}
+}
+
+func (c *Controller) CreateGame(w http.ResponseWriter, r *http.Request) {
+	converter := newgame.NewConverter(c.validator)
+	newGameReq, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	dailyGame, err := c.useCase.CreateDailyGame(currentUser, newGameReq.Options())
+	if err != nil {
+		if errors.Is(err, game.ErrDailyGameAlreadyPlayed) {
+			response.ErrConflict(w, err)
+			return
+		} else if errors.Is(err, game.ErrNoWordsFound) {
+			slogext.Error(slog.Default(), err)
+			response.ErrNotFound(w, err)
+			return
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
+		}
+	}
+
+	resp := dailygame.NewResponse(dailyGame)
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) GetGame(w http.ResponseWriter, r *http.Request) {
+	date, valErr := c.dateFromUrl(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	dailyGame, err := c.useCase.FindDailyGame(currentUser, date)
+	if err != nil {
+		if errors.Is(err, game.ErrDailyGameNotFound) {
+			response.ErrNotFound(w, err)
+			return
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
+		}
+	}
+
+	resp := dailygame.NewResponse(dailyGame)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) Guess(w http.ResponseWriter, r *http.Request) {
+	date, valErr := c.dateFromUrl(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	converter := guess.NewConverter(c.validator)
+	guessReq, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	dailyGame, err := c.useCase.GuessDaily(currentUser, date, guessReq.Word)
+	if err != nil {
+		var hardModeErr *entity.HardModeError
+		var wordLengthErr *entity.WordLengthError
+		if errors.Is(err, game.ErrDailyGameNotFound) {
+			response.ErrNotFound(w, err)
+			return
+		} else if errors.Is(err, game.ErrDailyGameFinished) {
+			response.ErrConflict(w, err)
+			return
+		} else if errors.As(err, &wordLengthErr) {
+			response.
+				NewValidationError().
+				AddFieldError("word", fmt.Sprintf("The word must consist of exactly %d letters", wordLengthErr.Length)).
+				ErrValidation(w)
+			return
+		} else if errors.As(err, &hardModeErr) {
+			valErr := response.NewValidationError()
+			for _, violation := range hardModeErr.Violations {
+				valErr.AddFieldError("word", violation)
+			}
+			valErr.ErrValidation(w)
+			return
+		} else if errors.Is(err, game.ErrIncorrectWord) {
+			response.
+				NewValidationError().
+				AddFieldError("word", "The word must be a Russian noun from the dictionary").
+				ErrValidation(w)
+			return
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
+		}
+	}
+
+	resp := dailygame.NewResponse(dailyGame)
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) dateFromUrl(r *http.Request) (time.Time, *response.ValidationError) {
+	date, err := c.useCase.ParseDate(chi.URLParam(r, "date"))
+	if err != nil {
+		return time.Time{}, response.
+			NewValidationError().
+			AddFieldError("date", "The 'date' parameter must be a date in the YYYY-MM-DD format.")
+	}
+
+	return date, nil
+}
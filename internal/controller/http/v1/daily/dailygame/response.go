@@ -0,0 +1,19 @@
+package dailygame
+
+import (
+	"github.com/Markard/wordka/internal/controller/http/v1/game/currentgame"
+	"github.com/Markard/wordka/internal/entity"
+	"time"
+)
+
+type Response struct {
+	Date string `json:"date"`
+	*currentgame.Response
+}
+
+func NewResponse(game *entity.Game) *Response {
+	return &Response{
+		Date:     game.DailyPuzzle.Date.Format(time.DateOnly),
+		Response: currentgame.NewResponse(game),
+	}
+}
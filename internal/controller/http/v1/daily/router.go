@@ -0,0 +1,18 @@
+package daily
+
+import (
+	"github.com/Markard/wordka/internal/usecase/game"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"github.com/go-chi/chi/v5"
+)
+
+func CreateRouter(val validator.ProjectValidator, useCase *game.UseCase) *chi.Mux {
+	r := chi.NewRouter()
+	c := NewController(useCase, val)
+
+	r.Post("/", c.CreateGame)
+	r.Get("/{date}", c.GetGame)
+	r.Post("/{date}/guess", c.Guess)
+
+	return r
+}
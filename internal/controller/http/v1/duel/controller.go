@@ -0,0 +1,142 @@
+package duel
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Markard/wordka/internal/controller/http/v1/duel/duelgame"
+	"github.com/Markard/wordka/internal/controller/http/v1/duel/join"
+	"github.com/Markard/wordka/internal/controller/http/v1/game/guess"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/infra/middleware/jwt"
+	"github.com/Markard/wordka/internal/usecase/duel"
+	"github.com/Markard/wordka/pkg/http/response"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"github.com/Markard/wordka/pkg/slogext"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type Controller struct {
+	useCase   *duel.UseCase
+	validator validator.ProjectValidator
+}
+
+func NewController(useCase *duel.UseCase, validator validator.ProjectValidator) *Controller {
+	return &Controller{useCase: useCase, validator: validator}
+}
+
+func (c *Controller) CreateDuel(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	createdDuel, err := c.useCase.CreateDuel(currentUser)
+	if err != nil {
+		if errors.Is(err, duel.ErrNoWordsFound) {
+			slogext.Error(slog.Default(), err)
+			response.ErrNotFound(w, err)
+			return
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
+		}
+	}
+
+	resp := duelgame.NewResponse(createdDuel, currentUser)
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) JoinDuel(w http.ResponseWriter, r *http.Request) {
+	converter := join.NewConverter(c.validator)
+	joinReq, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	joinedDuel, err := c.useCase.JoinDuel(currentUser, strings.ToUpper(joinReq.InviteCode))
+	if err != nil {
+		if errors.Is(err, duel.ErrDuelNotFound) {
+			response.ErrNotFound(w, err)
+			return
+		} else if errors.Is(err, duel.ErrDuelOwnInvite) || errors.Is(err, duel.ErrDuelAlreadyStarted) {
+			response.ErrConflict(w, err)
+			return
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
+		}
+	}
+
+	resp := duelgame.NewResponse(joinedDuel, currentUser)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) GetDuel(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	foundDuel, err := c.useCase.FindDuel(currentUser, id)
+	if err != nil {
+		if errors.Is(err, duel.ErrDuelNotFound) {
+			response.ErrNotFound(w, err)
+			return
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
+		}
+	}
+
+	resp := duelgame.NewResponse(foundDuel, currentUser)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) Guess(w http.ResponseWriter, r *http.Request) {
+	converter := guess.NewConverter(c.validator)
+	guessReq, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	playedDuel, err := c.useCase.Guess(currentUser, id, guessReq.Word)
+	if err != nil {
+		var wordLengthErr *entity.WordLengthError
+		if errors.Is(err, duel.ErrDuelNotFound) {
+			response.ErrNotFound(w, err)
+			return
+		} else if errors.Is(err, duel.ErrDuelGameFinished) || errors.Is(err, duel.ErrDuelNotStarted) {
+			response.ErrConflict(w, err)
+			return
+		} else if errors.As(err, &wordLengthErr) {
+			response.
+				NewValidationError().
+				AddFieldError("word", fmt.Sprintf("The word must consist of exactly %d letters", wordLengthErr.Length)).
+				ErrValidation(w)
+			return
+		} else if errors.Is(err, duel.ErrIncorrectWord) {
+			response.
+				NewValidationError().
+				AddFieldError("word", "The word must be a Russian noun from the dictionary").
+				ErrValidation(w)
+			return
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
+		}
+	}
+
+	resp := duelgame.NewResponse(playedDuel, currentUser)
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
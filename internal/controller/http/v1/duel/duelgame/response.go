@@ -0,0 +1,77 @@
+package duelgame
+
+import (
+	"github.com/Markard/wordka/internal/controller/http/v1/game/currentgame"
+	"github.com/Markard/wordka/internal/entity"
+)
+
+const (
+	ResultWon  = "won"
+	ResultLost = "lost"
+	ResultDraw = "draw"
+)
+
+// Opponent shows the progress of the other player as letter states only, never the letters themselves.
+type Opponent struct {
+	Name      string     `json:"name"`
+	IsPlaying bool       `json:"is_playing"`
+	IsWon     *bool      `json:"is_won"`
+	Guesses   [][]string `json:"guesses"`
+}
+
+type Response struct {
+	Id         int64                 `json:"id"`
+	InviteCode string                `json:"invite_code"`
+	Status     string                `json:"status"`
+	Result     *string               `json:"result"`
+	Me         *currentgame.Response `json:"me"`
+	Opponent   *Opponent             `json:"opponent"`
+}
+
+func NewResponse(duel *entity.Duel, currentUser *entity.User) *Response {
+	resp := &Response{
+		Id:         duel.Id,
+		InviteCode: duel.InviteCode,
+		Status:     duel.Status,
+	}
+
+	if game := duel.GameOf(currentUser.Id); game != nil {
+		resp.Me = currentgame.NewResponse(game)
+	}
+	if opponent := duel.OpponentOf(currentUser.Id); opponent != nil {
+		resp.Opponent = newOpponent(opponent, duel.GameOf(opponent.Id))
+	}
+
+	if duel.Status == entity.DuelStatusFinished {
+		result := ResultDraw
+		if duel.WinnerId.Valid && duel.WinnerId.Int64 == currentUser.Id {
+			result = ResultWon
+		} else if duel.WinnerId.Valid {
+			result = ResultLost
+		}
+		resp.Result = &result
+	}
+
+	return resp
+}
+
+func newOpponent(user *entity.User, game *entity.Game) *Opponent {
+	opponent := &Opponent{Name: user.Name, Guesses: make([][]string, 0)}
+	if game == nil {
+		return opponent
+	}
+
+	opponent.IsPlaying = game.IsPlaying
+	if game.IsWon.Valid {
+		opponent.IsWon = &game.IsWon.Bool
+	}
+	for _, results := range game.Board() {
+		states := make([]string, 0, len(results))
+		for _, result := range results {
+			states = append(states, string(result.State))
+		}
+		opponent.Guesses = append(opponent.Guesses, states)
+	}
+
+	return opponent
+}
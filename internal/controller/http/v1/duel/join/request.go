@@ -0,0 +1,5 @@
+package join
+
+type Request struct {
+	InviteCode string `json:"invite_code" validate:"required,len=8,alphanum"`
+}
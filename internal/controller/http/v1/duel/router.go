@@ -0,0 +1,19 @@
+package duel
+
+import (
+	"github.com/Markard/wordka/internal/usecase/duel"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"github.com/go-chi/chi/v5"
+)
+
+func CreateRouter(val validator.ProjectValidator, useCase *duel.UseCase) *chi.Mux {
+	r := chi.NewRouter()
+	c := NewController(useCase, val)
+
+	r.Post("/", c.CreateDuel)
+	r.Post("/join", c.JoinDuel)
+	r.Get("/{id:[0-9]+}", c.GetDuel)
+	r.Post("/{id:[0-9]+}/guess", c.Guess)
+
+	return r
+}
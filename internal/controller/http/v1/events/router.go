@@ -0,0 +1,15 @@
+package events
+
+import (
+	"github.com/Markard/wordka/internal/infra/eventbus"
+	"github.com/go-chi/chi/v5"
+)
+
+func CreateRouter(bus eventbus.Bus) *chi.Mux {
+	r := chi.NewRouter()
+	c := NewController(bus)
+
+	r.Get("/", c.Stream)
+
+	return r
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/Markard/wordka/internal/controller/http/v1/game/currentgame"
 	"github.com/Markard/wordka/internal/controller/http/v1/game/guess"
+	"github.com/Markard/wordka/internal/controller/http/v1/game/newgame"
 	"github.com/Markard/wordka/internal/entity"
 	"github.com/Markard/wordka/internal/infra/middleware/jwt"
 	"github.com/Markard/wordka/internal/usecase/game"
@@ -44,8 +45,15 @@ func (c *Controller) GetCurrentGame(w http.ResponseWriter, r *http.Request) {
 }
 
 func (c *Controller) CreateGame(w http.ResponseWriter, r *http.Request) {
+	converter := newgame.NewConverter(c.validator)
+	newGameReq, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
 	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
-	currentGame, err := c.useCase.CreateGame(currentUser)
+	currentGame, err := c.useCase.CreateGame(currentUser, newGameReq.Options())
 
 	if err != nil {
 		if errors.Is(err, game.ErrCurrentGameAlreadyExists) {
@@ -76,18 +84,30 @@ func (c *Controller) Guess(w http.ResponseWriter, r *http.Request) {
 	}
 
 	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
-	fmt.Printf(currentUser.Email)
-	fmt.Printf(guessReq.Word)
-
 	currentGame, err := c.useCase.Guess(currentUser, guessReq.Word)
 	if err != nil {
+		var hardModeErr *entity.HardModeError
+		var wordLengthErr *entity.WordLengthError
 		if errors.Is(err, game.ErrCurrentGameNotFound) {
 			response.ErrNotFound(w, err)
 			return
+		} else if errors.As(err, &wordLengthErr) {
+			response.
+				NewValidationError().
+				AddFieldError("word", fmt.Sprintf("The word must consist of exactly %d letters", wordLengthErr.Length)).
+				ErrValidation(w)
+			return
+		} else if errors.As(err, &hardModeErr) {
+			valErr := response.NewValidationError()
+			for _, violation := range hardModeErr.Violations {
+				valErr.AddFieldError("word", violation)
+			}
+			valErr.ErrValidation(w)
+			return
 		} else if errors.Is(err, game.ErrIncorrectWord) {
 			response.
 				NewValidationError().
-				AddFieldError("word", "The word must be a Russian noun consisting of exactly 5 letters").
+				AddFieldError("word", "The word must be a Russian noun from the dictionary").
 				ErrValidation(w)
 			return
 		} else {
@@ -100,3 +120,22 @@ func (c *Controller) Guess(w http.ResponseWriter, r *http.Request) {
 	render.Status(r, http.StatusCreated)
 	render.JSON(w, r, resp)
 }
+
+func (c *Controller) Surrender(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	currentGame, err := c.useCase.Surrender(currentUser)
+	if err != nil {
+		if errors.Is(err, game.ErrCurrentGameNotFound) {
+			response.ErrNotFound(w, err)
+			return
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
+		}
+	}
+
+	resp := currentgame.NewResponse(currentGame)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
@@ -6,6 +6,7 @@ import (
 
 type Letter struct {
 	Letter            string `json:"letter"`
+	State             string `json:"state"`
 	IsInWord          bool   `json:"is_in_word"`
 	IsCorrectPosition bool   `json:"is_correct_position"`
 }
@@ -15,33 +16,41 @@ type Guess struct {
 }
 
 type Response struct {
-	IsWon   *bool    `json:"is_won"`
-	Guesses []*Guess `json:"guesses"`
+	IsWon      *bool    `json:"is_won"`
+	IsHardMode bool     `json:"is_hard_mode"`
+	WordLength int      `json:"word_length"`
+	GuessLimit int8     `json:"guess_limit"`
+	Word       *string  `json:"word"`
+	Guesses    []*Guess `json:"guesses"`
 }
 
 func NewResponse(game *entity.Game) *Response {
 	guesses := make([]*Guess, 0)
-	for _, g := range game.Guesses {
+	for _, results := range game.Board() {
 		guess := &Guess{}
-		for wPos, wr := range g.Word.AsRunes() {
-			l := &Letter{Letter: string(wr)}
-			for swPos, swr := range game.Word.AsRunes() {
-				if swr == wr {
-					l.IsInWord = true
-					if swPos == wPos {
-						l.IsCorrectPosition = true
-					}
-				}
-			}
-			guess.Letters = append(guess.Letters, l)
+		for _, result := range results {
+			guess.Letters = append(guess.Letters, &Letter{
+				Letter:            string(result.Letter),
+				State:             string(result.State),
+				IsInWord:          result.State != entity.LetterStateAbsent,
+				IsCorrectPosition: result.State == entity.LetterStateCorrect,
+			})
 		}
 		guesses = append(guesses, guess)
 	}
 
-	resp := &Response{Guesses: guesses}
+	resp := &Response{
+		IsHardMode: game.IsHardMode,
+		WordLength: game.Word.Length,
+		GuessLimit: game.GuessLimit,
+		Guesses:    guesses,
+	}
 	if game.IsWon.Valid {
 		resp.IsWon = &game.IsWon.Bool
 	}
+	if !game.IsPlaying {
+		resp.Word = &game.Word.Word
+	}
 
 	return resp
 }
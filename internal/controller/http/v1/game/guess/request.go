@@ -1,5 +1,7 @@
 package guess
 
+// Request only bounds the word by the shortest and longest supported lengths,
+// the exact length depends on the game and is checked when the guess is made.
 type Request struct {
-	Word string `json:"word" validate:"required,len=5"`
+	Word string `json:"word" validate:"required,min=4,max=8"`
 }
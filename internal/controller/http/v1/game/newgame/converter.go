@@ -0,0 +1,34 @@
+package newgame
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/Markard/wordka/pkg/http/response"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"io"
+	"net/http"
+)
+
+type Converter struct {
+	validator validator.ProjectValidator
+}
+
+func NewConverter(validator validator.ProjectValidator) *Converter {
+	return &Converter{validator: validator}
+}
+
+// ValidateAndApply accepts an empty body, so a game with default options can be created without one.
+func (c *Converter) ValidateAndApply(r *http.Request) (*Request, *response.ValidationError) {
+	newGameReq := &Request{}
+
+	err := json.NewDecoder(r.Body).Decode(newGameReq)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, response.NewValidationError().AddFieldError("body", err.Error())
+	}
+
+	if errVal := c.validator.Struct(newGameReq); errVal != nil {
+		return nil, errVal
+	}
+
+	return newGameReq, nil
+}
@@ -0,0 +1,12 @@
+package newgame
+
+import "github.com/Markard/wordka/internal/entity"
+
+type Request struct {
+	HardMode   bool `json:"hard_mode"`
+	WordLength int  `json:"word_length" validate:"omitempty,min=4,max=8"`
+}
+
+func (r *Request) Options() entity.GameOptions {
+	return entity.GameOptions{IsHardMode: r.HardMode, WordLength: r.WordLength}
+}
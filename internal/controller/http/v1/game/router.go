@@ -13,6 +13,7 @@ func CreateRouter(val validator.ProjectValidator, useCase *game.UseCase) *chi.Mu
 	r.Get("/", c.GetCurrentGame)
 	r.Post("/", c.CreateGame)
 	r.Post("/guess", c.Guess)
+	r.Post("/surrender", c.Surrender)
 
 	return r
 }
@@ -0,0 +1,82 @@
+package history
+
+import (
+	"errors"
+	"github.com/Markard/wordka/internal/controller/http/v1/history/gamedetails"
+	"github.com/Markard/wordka/internal/controller/http/v1/history/gamelist"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/infra/middleware/jwt"
+	"github.com/Markard/wordka/internal/usecase/game"
+	"github.com/Markard/wordka/pkg/http/pagination"
+	"github.com/Markard/wordka/pkg/http/response"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"github.com/Markard/wordka/pkg/slogext"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Controller struct {
+	useCase   *game.UseCase
+	validator validator.ProjectValidator
+}
+
+func NewController(useCase *game.UseCase, validator validator.ProjectValidator) *Controller {
+	return &Controller{useCase: useCase, validator: validator}
+}
+
+func (c *Controller) GetGames(w http.ResponseWriter, r *http.Request) {
+	page, valErr := pagination.FromRequest(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	converter := gamelist.NewConverter(c.validator)
+	gameListReq, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	games, err := c.useCase.FindGames(currentUser, gameListReq.Filter(c.useCase.ParseDate), page.Cursor, page.Limit)
+	if err != nil {
+		response.ErrInternalServer(w)
+		slogext.Error(slog.Default(), err)
+		return
+	}
+
+	resp := pagination.NewResponse(gamelist.NewItems(games), page.Limit, func(item *gamelist.Item) int64 {
+		return item.Id
+	})
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) GetGame(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.ErrNotFound(w, game.ErrGameNotFound)
+		return
+	}
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	foundGame, err := c.useCase.FindGame(currentUser, id)
+	if err != nil {
+		if errors.Is(err, game.ErrGameNotFound) {
+			response.ErrNotFound(w, err)
+			return
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
+		}
+	}
+
+	resp := gamedetails.NewResponse(foundGame)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
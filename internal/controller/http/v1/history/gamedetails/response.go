@@ -0,0 +1,34 @@
+package gamedetails
+
+import (
+	"github.com/Markard/wordka/internal/controller/http/v1/game/currentgame"
+	"github.com/Markard/wordka/internal/entity"
+	"time"
+)
+
+type Response struct {
+	Id        int64     `json:"id"`
+	Mode      string    `json:"mode"`
+	Date      *string   `json:"date"`
+	IsPlaying bool      `json:"is_playing"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	*currentgame.Response
+}
+
+func NewResponse(game *entity.Game) *Response {
+	resp := &Response{
+		Id:        game.Id,
+		Mode:      game.Mode,
+		IsPlaying: game.IsPlaying,
+		CreatedAt: game.CreatedAt,
+		UpdatedAt: game.UpdatedAt,
+		Response:  currentgame.NewResponse(game),
+	}
+	if game.DailyPuzzle != nil {
+		date := game.DailyPuzzle.Date.Format(time.DateOnly)
+		resp.Date = &date
+	}
+
+	return resp
+}
@@ -0,0 +1,31 @@
+package gamelist
+
+import (
+	"github.com/Markard/wordka/pkg/http/response"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"net/http"
+)
+
+type Converter struct {
+	validator validator.ProjectValidator
+}
+
+func NewConverter(validator validator.ProjectValidator) *Converter {
+	return &Converter{validator: validator}
+}
+
+func (c *Converter) ValidateAndApply(r *http.Request) (*Request, *response.ValidationError) {
+	query := r.URL.Query()
+	gameListReq := &Request{
+		Result: query.Get("result"),
+		Mode:   query.Get("mode"),
+		From:   query.Get("from"),
+		To:     query.Get("to"),
+	}
+
+	if errVal := c.validator.Struct(gameListReq); errVal != nil {
+		return nil, errVal
+	}
+
+	return gameListReq, nil
+}
@@ -0,0 +1,44 @@
+package gamelist
+
+import (
+	"database/sql"
+	"github.com/Markard/wordka/internal/entity"
+	"time"
+)
+
+const (
+	ResultWon     = "won"
+	ResultLost    = "lost"
+	ResultPlaying = "playing"
+)
+
+type Request struct {
+	Result string `validate:"omitempty,oneof=won lost playing"`
+	Mode   string `validate:"omitempty,oneof=practice daily"`
+	From   string `validate:"omitempty,datetime=2006-01-02"`
+	To     string `validate:"omitempty,datetime=2006-01-02"`
+}
+
+// Filter converts the request into a game filter, parsing dates with parseDate.
+// The 'to' date is inclusive.
+func (r *Request) Filter(parseDate func(date string) (time.Time, error)) entity.GameFilter {
+	filter := entity.GameFilter{Mode: r.Mode}
+
+	switch r.Result {
+	case ResultWon:
+		filter.IsWon = sql.NullBool{Bool: true, Valid: true}
+	case ResultLost:
+		filter.IsWon = sql.NullBool{Bool: false, Valid: true}
+	case ResultPlaying:
+		filter.IsPlaying = sql.NullBool{Bool: true, Valid: true}
+	}
+
+	if from, err := parseDate(r.From); err == nil {
+		filter.From = from
+	}
+	if to, err := parseDate(r.To); err == nil {
+		filter.To = to.AddDate(0, 0, 1)
+	}
+
+	return filter
+}
@@ -0,0 +1,56 @@
+package gamelist
+
+import (
+	"github.com/Markard/wordka/internal/entity"
+	"time"
+)
+
+type Item struct {
+	Id         int64     `json:"id"`
+	Mode       string    `json:"mode"`
+	Date       *string   `json:"date"`
+	IsHardMode bool      `json:"is_hard_mode"`
+	IsPlaying  bool      `json:"is_playing"`
+	IsWon      *bool     `json:"is_won"`
+	WordLength int       `json:"word_length"`
+	Guesses    int       `json:"guesses"`
+	GuessLimit int8      `json:"guess_limit"`
+	Word       *string   `json:"word"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func NewItem(game *entity.Game) *Item {
+	item := &Item{
+		Id:         game.Id,
+		Mode:       game.Mode,
+		IsHardMode: game.IsHardMode,
+		IsPlaying:  game.IsPlaying,
+		WordLength: game.Word.Length,
+		Guesses:    len(game.Guesses),
+		GuessLimit: game.GuessLimit,
+		CreatedAt:  game.CreatedAt,
+		UpdatedAt:  game.UpdatedAt,
+	}
+	if game.DailyPuzzle != nil {
+		date := game.DailyPuzzle.Date.Format(time.DateOnly)
+		item.Date = &date
+	}
+	if game.IsWon.Valid {
+		item.IsWon = &game.IsWon.Bool
+	}
+	if !game.IsPlaying {
+		item.Word = &game.Word.Word
+	}
+
+	return item
+}
+
+func NewItems(games []*entity.Game) []*Item {
+	items := make([]*Item, 0, len(games))
+	for _, game := range games {
+		items = append(items, NewItem(game))
+	}
+
+	return items
+}
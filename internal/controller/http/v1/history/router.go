@@ -0,0 +1,17 @@
+package history
+
+import (
+	"github.com/Markard/wordka/internal/usecase/game"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"github.com/go-chi/chi/v5"
+)
+
+func CreateRouter(val validator.ProjectValidator, useCase *game.UseCase) *chi.Mux {
+	r := chi.NewRouter()
+	c := NewController(useCase, val)
+
+	r.Get("/", c.GetGames)
+	r.Get("/{id:[0-9]+}", c.GetGame)
+
+	return r
+}
@@ -0,0 +1,78 @@
+package leaderboard
+
+import (
+	"errors"
+	"github.com/Markard/wordka/internal/controller/http/v1/leaderboard/ranking"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/infra/middleware/jwt"
+	"github.com/Markard/wordka/internal/usecase/game"
+	"github.com/Markard/wordka/internal/usecase/leaderboard"
+	"github.com/Markard/wordka/pkg/http/response"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"github.com/Markard/wordka/pkg/slogext"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Controller struct {
+	useCase     *leaderboard.UseCase
+	gameUseCase *game.UseCase
+	validator   validator.ProjectValidator
+}
+
+func NewController(
+	useCase *leaderboard.UseCase,
+	gameUseCase *game.UseCase,
+	validator validator.ProjectValidator,
+) *Controller {
+	return &Controller{useCase: useCase, gameUseCase: gameUseCase, validator: validator}
+}
+
+func (c *Controller) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	converter := ranking.NewConverter(c.validator)
+	rankingReq, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	kind := chi.URLParam(r, "kind")
+
+	var lb *leaderboard.Leaderboard
+	var err error
+	period, date := rankingReq.Period, ""
+	if kind == entity.LeaderboardKindDaily {
+		day := c.gameUseCase.Today()
+		if rankingReq.Date != "" {
+			if day, err = c.gameUseCase.ParseDate(rankingReq.Date); err != nil {
+				response.
+					NewValidationError().
+					AddFieldError("date", "The 'date' parameter must be a date in the YYYY-MM-DD format.").
+					ErrValidation(w)
+				return
+			}
+		}
+		period, date = "", day.Format(time.DateOnly)
+		lb, err = c.useCase.FindDailyLeaderboard(currentUser, day, rankingReq.Limit)
+	} else {
+		lb, err = c.useCase.FindPeriodLeaderboard(currentUser, kind, rankingReq.Period, rankingReq.Limit)
+	}
+	if err != nil {
+		if errors.Is(err, leaderboard.ErrUnknownLeaderboard) || errors.Is(err, leaderboard.ErrDailyPuzzleNotFound) {
+			response.ErrNotFound(w, err)
+			return
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
+		}
+	}
+
+	resp := ranking.NewResponse(kind, period, date, lb, currentUser)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
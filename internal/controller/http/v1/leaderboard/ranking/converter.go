@@ -0,0 +1,42 @@
+package ranking
+
+import (
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/pkg/http/response"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"net/http"
+	"strconv"
+)
+
+type Converter struct {
+	validator validator.ProjectValidator
+}
+
+func NewConverter(validator validator.ProjectValidator) *Converter {
+	return &Converter{validator: validator}
+}
+
+func (c *Converter) ValidateAndApply(r *http.Request) (*Request, *response.ValidationError) {
+	query := r.URL.Query()
+	rankingReq := &Request{
+		Period: query.Get("period"),
+		Date:   query.Get("date"),
+		Limit:  defaultLimit,
+	}
+	if rankingReq.Period == "" {
+		rankingReq.Period = entity.LeaderboardPeriodAll
+	}
+	if limit := query.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return nil, response.NewValidationError().AddFieldError("limit", "The 'limit' field must be a number.")
+		}
+		rankingReq.Limit = l
+	}
+
+	if errVal := c.validator.Struct(rankingReq); errVal != nil {
+		return nil, errVal
+	}
+
+	return rankingReq, nil
+}
@@ -0,0 +1,9 @@
+package ranking
+
+const defaultLimit = 10
+
+type Request struct {
+	Period string `validate:"oneof=all month week"`
+	Date   string `validate:"omitempty,datetime=2006-01-02"`
+	Limit  int    `validate:"min=1,max=100"`
+}
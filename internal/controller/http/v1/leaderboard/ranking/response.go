@@ -0,0 +1,48 @@
+package ranking
+
+import (
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/usecase/leaderboard"
+)
+
+type Ranking struct {
+	Rank         int     `json:"rank"`
+	Name         string  `json:"name"`
+	Score        float64 `json:"score"`
+	SolveSeconds *int    `json:"solve_seconds,omitempty"`
+	IsMe         bool    `json:"is_me"`
+}
+
+type Response struct {
+	Kind   string     `json:"kind"`
+	Period string     `json:"period,omitempty"`
+	Date   string     `json:"date,omitempty"`
+	Top    []*Ranking `json:"top"`
+	Me     *Ranking   `json:"me"`
+}
+
+func NewResponse(kind, period, date string, lb *leaderboard.Leaderboard, currentUser *entity.User) *Response {
+	resp := &Response{Kind: kind, Period: period, Date: date, Top: make([]*Ranking, 0, len(lb.Top))}
+	for _, r := range lb.Top {
+		resp.Top = append(resp.Top, newRanking(kind, r, currentUser))
+	}
+	if lb.Me != nil {
+		resp.Me = newRanking(kind, lb.Me, currentUser)
+	}
+
+	return resp
+}
+
+func newRanking(kind string, r *entity.Ranking, currentUser *entity.User) *Ranking {
+	ranking := &Ranking{
+		Rank:  r.Rank,
+		Name:  r.Name,
+		Score: r.Score,
+		IsMe:  r.UserId == currentUser.Id,
+	}
+	if kind == entity.LeaderboardKindDaily {
+		ranking.SolveSeconds = &r.SolveSeconds
+	}
+
+	return ranking
+}
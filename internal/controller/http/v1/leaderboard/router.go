@@ -0,0 +1,17 @@
+package leaderboard
+
+import (
+	"github.com/Markard/wordka/internal/usecase/game"
+	"github.com/Markard/wordka/internal/usecase/leaderboard"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"github.com/go-chi/chi/v5"
+)
+
+func CreateRouter(val validator.ProjectValidator, useCase *leaderboard.UseCase, gameUseCase *game.UseCase) *chi.Mux {
+	r := chi.NewRouter()
+	c := NewController(useCase, gameUseCase, val)
+
+	r.Get("/{kind}", c.GetLeaderboard)
+
+	return r
+}
@@ -1,8 +1,17 @@
 package v1
 
 import (
+	"github.com/Markard/wordka/internal/controller/http/v1/admin"
 	"github.com/Markard/wordka/internal/controller/http/v1/auth"
+	"github.com/Markard/wordka/internal/controller/http/v1/daily"
+	"github.com/Markard/wordka/internal/controller/http/v1/duel"
+	"github.com/Markard/wordka/internal/controller/http/v1/events"
 	"github.com/Markard/wordka/internal/controller/http/v1/game"
+	"github.com/Markard/wordka/internal/controller/http/v1/history"
+	"github.com/Markard/wordka/internal/controller/http/v1/leaderboard"
+	"github.com/Markard/wordka/internal/controller/http/v1/share"
+	"github.com/Markard/wordka/internal/controller/http/v1/user"
+	"github.com/Markard/wordka/internal/infra/eventbus"
 	"github.com/Markard/wordka/internal/infra/middleware"
 	"github.com/Markard/wordka/internal/usecase"
 	"github.com/Markard/wordka/pkg/http/validator"
@@ -16,12 +25,39 @@ func CreateRouter(
 ) *chi.Mux {
 	r := chi.NewRouter()
 
-	r.Mount("/", auth.CreateRouter(val, useCases.AuthUseCase))
+	r.Mount("/", auth.CreateRouter(val, useCases.AuthUseCase, middlewares))
 	r.Group(func(r chi.Router) {
 		r.Use(middlewares.JwtAuthenticator)
 
-		r.Mount("/games/current", game.CreateRouter(val, useCases.GameUseCase))
+		r.Mount("/games", history.CreateRouter(val, useCases.GameUseCase))
+		r.Mount("/games/{id:[0-9]+}/share", share.CreateRouter(useCases.ShareUseCase))
+		r.Mount("/users/me", user.CreateRouter(val, useCases.StatsUseCase, useCases.AuthUseCase, useCases.ProfileUseCase))
+		r.Mount("/leaderboards", leaderboard.CreateRouter(val, useCases.LeaderboardUseCase, useCases.GameUseCase))
+		r.Mount("/admin", admin.CreateRouter(val, useCases.AdminUseCase))
+
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.GameplayGuard)
+
+			r.Mount("/games/current", game.CreateRouter(val, useCases.GameUseCase))
+			r.Mount("/games/daily", daily.CreateRouter(val, useCases.GameUseCase))
+			r.Mount("/duels", duel.CreateRouter(val, useCases.DuelUseCase))
+		})
 	})
 
 	return r
 }
+
+// CreatePublicShareRouter serves shared results by their token, without authentication.
+func CreatePublicShareRouter(useCases *usecase.UseCases) *chi.Mux {
+	return share.CreatePublicRouter(useCases.ShareUseCase)
+}
+
+// CreateStreamRouter serves long-lived streaming endpoints, which must not be cut by the request timeout.
+func CreateStreamRouter(middlewares *middleware.Middlewares, bus eventbus.Bus) *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(middlewares.JwtAuthenticator)
+	r.Mount("/", events.CreateRouter(bus))
+
+	return r
+}
@@ -0,0 +1,110 @@
+package share
+
+import (
+	"errors"
+	"github.com/Markard/wordka/internal/controller/http/v1/share/publicresult"
+	"github.com/Markard/wordka/internal/controller/http/v1/share/sharedresult"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/infra/middleware/jwt"
+	"github.com/Markard/wordka/internal/usecase/share"
+	"github.com/Markard/wordka/pkg/http/response"
+	"github.com/Markard/wordka/pkg/slogext"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Controller struct {
+	useCase *share.UseCase
+}
+
+func NewController(useCase *share.UseCase) *Controller {
+	return &Controller{useCase: useCase}
+}
+
+func (c *Controller) Share(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	shareToken, err := c.useCase.Share(currentUser, id)
+	if err != nil {
+		if errors.Is(err, share.ErrGameNotFound) {
+			response.ErrNotFound(w, err)
+			return
+		} else if errors.Is(err, share.ErrGameNotFinished) {
+			response.ErrConflict(w, err)
+			return
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
+		}
+	}
+
+	resp := sharedresult.NewResponse(shareToken)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) GetShare(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	shareToken, err := c.useCase.FindShare(currentUser, id)
+	if err != nil {
+		if errors.Is(err, share.ErrGameNotFound) || errors.Is(err, share.ErrShareNotFound) {
+			response.ErrNotFound(w, err)
+			return
+		} else if errors.Is(err, share.ErrGameNotFinished) {
+			response.ErrConflict(w, err)
+			return
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
+		}
+	}
+
+	resp := sharedresult.NewResponse(shareToken)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) Revoke(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	err := c.useCase.Revoke(currentUser, id)
+	if err != nil {
+		if errors.Is(err, share.ErrGameNotFound) {
+			response.ErrNotFound(w, err)
+			return
+		} else if errors.Is(err, share.ErrGameNotFinished) {
+			response.ErrConflict(w, err)
+			return
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Controller) GetSharedResult(w http.ResponseWriter, r *http.Request) {
+	sharedGame, err := c.useCase.FindSharedGame(chi.URLParam(r, "token"))
+	if err != nil {
+		if errors.Is(err, share.ErrShareNotFound) {
+			response.ErrNotFound(w, err)
+			return
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
+		}
+	}
+
+	resp := publicresult.NewResponse(sharedGame)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
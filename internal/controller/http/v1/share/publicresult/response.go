@@ -0,0 +1,29 @@
+package publicresult
+
+import (
+	"github.com/Markard/wordka/internal/entity"
+)
+
+// Response exposes only the colors of the board, so the shared link never spoils the word.
+type Response struct {
+	Header  string     `json:"header"`
+	IsWon   bool       `json:"is_won"`
+	Guesses [][]string `json:"guesses"`
+}
+
+func NewResponse(game *entity.Game) *Response {
+	guesses := make([][]string, 0, len(game.Guesses))
+	for _, results := range game.Board() {
+		states := make([]string, 0, len(results))
+		for _, result := range results {
+			states = append(states, string(result.State))
+		}
+		guesses = append(guesses, states)
+	}
+
+	return &Response{
+		Header:  game.ShareHeader(),
+		IsWon:   game.IsWon.Bool,
+		Guesses: guesses,
+	}
+}
@@ -0,0 +1,27 @@
+package share
+
+import (
+	"github.com/Markard/wordka/internal/usecase/share"
+	"github.com/go-chi/chi/v5"
+)
+
+func CreateRouter(useCase *share.UseCase) *chi.Mux {
+	r := chi.NewRouter()
+	c := NewController(useCase)
+
+	r.Get("/", c.GetShare)
+	r.Post("/", c.Share)
+	r.Delete("/", c.Revoke)
+
+	return r
+}
+
+// CreatePublicRouter serves shared results to anyone who has the link, without authentication.
+func CreatePublicRouter(useCase *share.UseCase) *chi.Mux {
+	r := chi.NewRouter()
+	c := NewController(useCase)
+
+	r.Get("/{token}", c.GetSharedResult)
+
+	return r
+}
@@ -0,0 +1,26 @@
+package sharedresult
+
+import (
+	"github.com/Markard/wordka/internal/entity"
+)
+
+type Response struct {
+	Header string `json:"header"`
+	Grid   string `json:"grid"`
+	Text   string `json:"text"`
+	Token  string `json:"token"`
+	Path   string `json:"path"`
+}
+
+func NewResponse(shareToken *entity.ShareToken) *Response {
+	header := shareToken.Game.ShareHeader()
+	grid := shareToken.Game.ShareGrid()
+
+	return &Response{
+		Header: header,
+		Grid:   grid,
+		Text:   header + "\n\n" + grid,
+		Token:  shareToken.Token,
+		Path:   "/s/" + shareToken.Token,
+	}
+}
@@ -0,0 +1,7 @@
+package changepassword
+
+// Request sets a new password. CurrentPassword may only be empty for users who have no password yet.
+type Request struct {
+	CurrentPassword string `json:"current_password"`
+	Password        string `json:"password" validate:"required,min=8,max=16,validate_password"`
+}
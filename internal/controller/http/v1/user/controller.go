@@ -0,0 +1,182 @@
+package user
+
+import (
+	"errors"
+	"github.com/Markard/wordka/internal/controller/http/v1/auth/login"
+	"github.com/Markard/wordka/internal/controller/http/v1/user/changepassword"
+	"github.com/Markard/wordka/internal/controller/http/v1/user/deleteaccount"
+	"github.com/Markard/wordka/internal/controller/http/v1/user/updateprofile"
+	"github.com/Markard/wordka/internal/controller/http/v1/user/userexport"
+	"github.com/Markard/wordka/internal/controller/http/v1/user/userprofile"
+	"github.com/Markard/wordka/internal/controller/http/v1/user/userstats"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/infra/middleware/jwt"
+	"github.com/Markard/wordka/internal/usecase/auth"
+	"github.com/Markard/wordka/internal/usecase/profile"
+	"github.com/Markard/wordka/internal/usecase/stats"
+	"github.com/Markard/wordka/pkg/http/response"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"github.com/Markard/wordka/pkg/slogext"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Controller struct {
+	statsUseCase   *stats.UseCase
+	authUseCase    *auth.UseCase
+	profileUseCase *profile.UseCase
+	validator      validator.ProjectValidator
+}
+
+func NewController(
+	statsUseCase *stats.UseCase,
+	authUseCase *auth.UseCase,
+	profileUseCase *profile.UseCase,
+	validator validator.ProjectValidator,
+) *Controller {
+	return &Controller{
+		statsUseCase:   statsUseCase,
+		authUseCase:    authUseCase,
+		profileUseCase: profileUseCase,
+		validator:      validator,
+	}
+}
+
+func (c *Controller) GetProfile(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+
+	resp := userprofile.NewResponse(currentUser)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	converter := updateprofile.NewConverter(c.validator)
+	updateRequest, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	user, err := c.profileUseCase.Update(currentUser, updateRequest.Changes())
+	if err != nil {
+		if errors.Is(err, profile.ErrEmailAlreadyTaken) {
+			response.ErrConflict(w, err)
+			return
+		} else if errors.Is(err, profile.ErrWrongPassword) {
+			response.
+				NewValidationError().
+				AddFieldError("current_password", "The current password is required to change the email").
+				ErrValidation(w)
+			return
+		} else if errors.Is(err, entity.ErrUnknownTimezone) {
+			response.
+				NewValidationError().
+				AddFieldError("timezone", "The timezone must be an IANA name, e.g. Europe/Moscow").
+				ErrValidation(w)
+			return
+		} else if errors.Is(err, entity.ErrUnsupportedLocale) {
+			response.
+				NewValidationError().
+				AddFieldError("locale", err.Error()).
+				ErrValidation(w)
+			return
+		} else if errors.Is(err, profile.ErrVerificationNotSent) {
+			// The email is changed anyway and the verification can be resent.
+			slogext.Error(slog.Default(), err)
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+			return
+		}
+	}
+
+	resp := userprofile.NewResponse(user)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	converter := deleteaccount.NewConverter(c.validator)
+	deleteRequest, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	if err := c.profileUseCase.Delete(currentUser, deleteRequest.Password); err != nil {
+		if errors.Is(err, profile.ErrWrongPassword) {
+			response.
+				NewValidationError().
+				AddFieldError("password", "The password is incorrect").
+				ErrValidation(w)
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Controller) ExportData(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	export, err := c.profileUseCase.Export(currentUser)
+	if err != nil {
+		response.ErrInternalServer(w)
+		slogext.Error(slog.Default(), err)
+		return
+	}
+
+	resp := userexport.NewResponse(export)
+	w.Header().Set("Content-Disposition", `attachment; filename="wordka-export.json"`)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func (c *Controller) GetStats(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	userStats, err := c.statsUseCase.FindStats(currentUser)
+	if err != nil {
+		response.ErrInternalServer(w)
+		slogext.Error(slog.Default(), err)
+		return
+	}
+
+	resp := userstats.NewResponse(userStats)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+// ChangePassword responds with the tokens of a new session, as every other session is logged out.
+func (c *Controller) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	converter := changepassword.NewConverter(c.validator)
+	changeRequest, valErr := converter.ValidateAndApply(r)
+	if valErr != nil {
+		valErr.ErrValidation(w)
+		return
+	}
+
+	currentUser, _ := r.Context().Value(jwt.CurrentUserCtxKey).(*entity.User)
+	tokens, err := c.authUseCase.ChangePassword(currentUser, changeRequest.CurrentPassword, changeRequest.Password)
+	if err != nil {
+		if errors.Is(err, auth.ErrWrongPassword) {
+			response.
+				NewValidationError().
+				AddFieldError("current_password", "The current password is incorrect").
+				ErrValidation(w)
+		} else {
+			response.ErrInternalServer(w)
+			slogext.Error(slog.Default(), err)
+		}
+		return
+	}
+
+	resp := login.NewResponse(tokens)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
@@ -0,0 +1,6 @@
+package deleteaccount
+
+// Request confirms the deletion with the password, users without a password send none.
+type Request struct {
+	Password string `json:"password"`
+}
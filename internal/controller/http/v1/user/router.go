@@ -0,0 +1,28 @@
+package user
+
+import (
+	"github.com/Markard/wordka/internal/usecase/auth"
+	"github.com/Markard/wordka/internal/usecase/profile"
+	"github.com/Markard/wordka/internal/usecase/stats"
+	"github.com/Markard/wordka/pkg/http/validator"
+	"github.com/go-chi/chi/v5"
+)
+
+func CreateRouter(
+	val validator.ProjectValidator,
+	statsUseCase *stats.UseCase,
+	authUseCase *auth.UseCase,
+	profileUseCase *profile.UseCase,
+) *chi.Mux {
+	r := chi.NewRouter()
+	c := NewController(statsUseCase, authUseCase, profileUseCase, val)
+
+	r.Get("/", c.GetProfile)
+	r.Patch("/", c.UpdateProfile)
+	r.Delete("/", c.DeleteAccount)
+	r.Get("/export", c.ExportData)
+	r.Get("/stats", c.GetStats)
+	r.Put("/password", c.ChangePassword)
+
+	return r
+}
@@ -0,0 +1,21 @@
+package updateprofile
+
+import "github.com/Markard/wordka/internal/usecase/profile"
+
+type Request struct {
+	Name            *string `json:"name" validate:"omitempty,min=3,max=255"`
+	Email           *string `json:"email" validate:"omitempty,email,max=255"`
+	Locale          *string `json:"locale" validate:"omitempty,oneof=ru en"`
+	Timezone        *string `json:"timezone" validate:"omitempty,max=64"`
+	CurrentPassword string  `json:"current_password"`
+}
+
+func (r *Request) Changes() *profile.Changes {
+	return &profile.Changes{
+		Name:            r.Name,
+		Email:           r.Email,
+		Locale:          r.Locale,
+		Timezone:        r.Timezone,
+		CurrentPassword: r.CurrentPassword,
+	}
+}
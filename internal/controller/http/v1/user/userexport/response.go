@@ -0,0 +1,133 @@
+package userexport
+
+import (
+	"github.com/Markard/wordka/internal/controller/http/v1/user/userprofile"
+	"github.com/Markard/wordka/internal/controller/http/v1/user/userstats"
+	"github.com/Markard/wordka/internal/usecase/profile"
+	"time"
+)
+
+type Response struct {
+	Profile     *userprofile.Response `json:"profile"`
+	Stats       *userstats.Response   `json:"stats"`
+	Games       []*Game               `json:"games"`
+	Duels       []*Duel               `json:"duels"`
+	Sessions    []*Session            `json:"sessions"`
+	ShareTokens []*ShareToken         `json:"share_tokens"`
+	Identities  []*Identity           `json:"identities"`
+}
+
+type Game struct {
+	Id         int64     `json:"id"`
+	Mode       string    `json:"mode"`
+	Word       string    `json:"word,omitempty"`
+	IsHardMode bool      `json:"is_hard_mode"`
+	IsPlaying  bool      `json:"is_playing"`
+	IsWon      bool      `json:"is_won"`
+	GuessLimit int8      `json:"guess_limit"`
+	Guesses    []*Guess  `json:"guesses"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type Guess struct {
+	Word      string    `json:"word"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Duel struct {
+	Id        int64     `json:"id"`
+	Status    string    `json:"status"`
+	IsCreator bool      `json:"is_creator"`
+	IsWinner  bool      `json:"is_winner"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Session struct {
+	CreatedAt time.Time `json:"created_at"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+type ShareToken struct {
+	GameId    int64     `json:"game_id"`
+	Token     string    `json:"token"`
+	CreatedAt time.Time `json:"created_at"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+type Identity struct {
+	Provider    string    `json:"provider"`
+	Subject     string    `json:"subject"`
+	Email       string    `json:"email,omitempty"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+func NewResponse(export *profile.Export) *Response {
+	resp := &Response{
+		Profile:     userprofile.NewResponse(export.User),
+		Games:       make([]*Game, 0, len(export.Games)),
+		Duels:       make([]*Duel, 0, len(export.Duels)),
+		Sessions:    make([]*Session, 0, len(export.Sessions)),
+		ShareTokens: make([]*ShareToken, 0, len(export.ShareTokens)),
+		Identities:  make([]*Identity, 0, len(export.Identities)),
+	}
+	if export.Stats != nil {
+		resp.Stats = userstats.NewResponse(export.Stats)
+	}
+
+	for _, game := range export.Games {
+		g := &Game{
+			Id:         game.Id,
+			Mode:       game.Mode,
+			IsHardMode: game.IsHardMode,
+			IsPlaying:  game.IsPlaying,
+			IsWon:      game.IsWon.Bool,
+			GuessLimit: game.GuessLimit,
+			Guesses:    make([]*Guess, 0, len(game.Guesses)),
+			CreatedAt:  game.CreatedAt,
+			UpdatedAt:  game.UpdatedAt,
+		}
+		// The answer of a game in progress stays a secret even in the export.
+		if !game.IsPlaying {
+			g.Word = game.Word.Word
+		}
+		for _, guess := range game.Guesses {
+			g.Guesses = append(g.Guesses, &Guess{Word: guess.Word.Word, CreatedAt: guess.CreatedAt})
+		}
+		resp.Games = append(resp.Games, g)
+	}
+
+	for _, duel := range export.Duels {
+		resp.Duels = append(resp.Duels, &Duel{
+			Id:        duel.Id,
+			Status:    duel.Status,
+			IsCreator: duel.CreatorId == export.User.Id,
+			IsWinner:  duel.WinnerId.Valid && duel.WinnerId.Int64 == export.User.Id,
+			CreatedAt: duel.CreatedAt,
+		})
+	}
+
+	for _, session := range export.Sessions {
+		resp.Sessions = append(resp.Sessions, &Session{CreatedAt: session.CreatedAt, RevokedAt: session.RevokedAt.Time})
+	}
+
+	for _, shareToken := range export.ShareTokens {
+		resp.ShareTokens = append(resp.ShareTokens, &ShareToken{
+			GameId:    shareToken.GameId,
+			Token:     shareToken.Token,
+			CreatedAt: shareToken.CreatedAt,
+			RevokedAt: shareToken.RevokedAt.Time,
+		})
+	}
+
+	for _, identity := range export.Identities {
+		resp.Identities = append(resp.Identities, &Identity{
+			Provider:    identity.Provider,
+			Subject:     identity.Subject,
+			Email:       identity.Email,
+			LastLoginAt: identity.LastLoginAt,
+		})
+	}
+
+	return resp
+}
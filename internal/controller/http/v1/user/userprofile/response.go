@@ -0,0 +1,34 @@
+package userprofile
+
+import (
+	"github.com/Markard/wordka/internal/entity"
+	"time"
+)
+
+type Response struct {
+	Id              int64     `json:"id"`
+	Name            string    `json:"name"`
+	Email           string    `json:"email"`
+	Role            string    `json:"role"`
+	HasPassword     bool      `json:"has_password"`
+	EmailVerifiedAt time.Time `json:"email_verified_at"`
+	Locale          string    `json:"locale"`
+	Timezone        string    `json:"timezone"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func NewResponse(user *entity.User) *Response {
+	return &Response{
+		Id:              user.Id,
+		Name:            user.Name,
+		Email:           user.Email,
+		Role:            user.Role,
+		HasPassword:     user.HasPassword(),
+		EmailVerifiedAt: user.EmailVerifiedAt.Time,
+		Locale:          user.Locale,
+		Timezone:        user.Timezone,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+	}
+}
@@ -0,0 +1,27 @@
+package userstats
+
+import (
+	"github.com/Markard/wordka/internal/entity"
+)
+
+type Response struct {
+	GamesPlayed       int   `json:"games_played"`
+	GamesWon          int   `json:"games_won"`
+	WinPercentage     int   `json:"win_percentage"`
+	HardModeWins      int   `json:"hard_mode_wins"`
+	CurrentStreak     int   `json:"current_streak"`
+	MaxStreak         int   `json:"max_streak"`
+	GuessDistribution []int `json:"guess_distribution"`
+}
+
+func NewResponse(stats *entity.UserStats) *Response {
+	return &Response{
+		GamesPlayed:       stats.GamesPlayed,
+		GamesWon:          stats.GamesWon,
+		WinPercentage:     stats.WinPercentage(),
+		HardModeWins:      stats.HardModeWins,
+		CurrentStreak:     stats.CurrentStreak,
+		MaxStreak:         stats.MaxStreak,
+		GuessDistribution: stats.GuessDistribution,
+	}
+}
@@ -0,0 +1,23 @@
+package wellknown
+
+import (
+	"github.com/Markard/wordka/internal/usecase/auth"
+	"github.com/go-chi/render"
+	"net/http"
+)
+
+type Controller struct {
+	authUseCase *auth.UseCase
+}
+
+func NewController(authUseCase *auth.UseCase) *Controller {
+	return &Controller{authUseCase: authUseCase}
+}
+
+// GetJwks publishes the public keys of the tokens, so other services can verify them.
+// The keys change only on a restart after a rotation, so they are cached for a while.
+func (c *Controller) GetJwks(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, c.authUseCase.Jwks())
+}
@@ -0,0 +1,15 @@
+package wellknown
+
+import (
+	"github.com/Markard/wordka/internal/usecase/auth"
+	"github.com/go-chi/chi/v5"
+)
+
+func CreateRouter(authUseCase *auth.UseCase) *chi.Mux {
+	r := chi.NewRouter()
+	c := NewController(authUseCase)
+
+	r.Get("/jwks.json", c.GetJwks)
+
+	return r
+}
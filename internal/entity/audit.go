@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+const (
+	AuditTargetUser        = "user"
+	AuditTargetWord        = "word"
+	AuditTargetDailyPuzzle = "daily_puzzle"
+)
+
+// AuditEntry records a change made through the admin API: who did what to which target.
+type AuditEntry struct {
+	bun.BaseModel `bun:"table:audit_log"`
+
+	Id         int64          `bun:"id,pk,autoincrement"`
+	ActorId    int64          `bun:"actor_id,notnull"`
+	Action     string         `bun:"action,notnull"`
+	TargetType string         `bun:"target_type,notnull"`
+	Target     string         `bun:"target,notnull"`
+	Details    map[string]any `bun:"details,type:jsonb,notnull"`
+	CreatedAt  time.Time      `bun:"created_at,notnull"`
+
+	Actor *User `bun:"rel:belongs-to,join:actor_id=id"`
+}
+
+func NewAuditEntry(actor *User, action, targetType, target string, details map[string]any) *AuditEntry {
+	if details == nil {
+		details = map[string]any{}
+	}
+
+	return &AuditEntry{
+		ActorId:    actor.Id,
+		Action:     action,
+		TargetType: targetType,
+		Target:     target,
+		Details:    details,
+		CreatedAt:  time.Now(),
+		Actor:      actor,
+	}
+}
@@ -0,0 +1,152 @@
+package entity
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+const (
+	DuelStatusWaiting  = "waiting"
+	DuelStatusPlaying  = "playing"
+	DuelStatusFinished = "finished"
+)
+
+const inviteCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+var (
+	ErrDuelOwnInvite      = errors.New("you cannot join your own duel")
+	ErrDuelAlreadyStarted = errors.New("the duel already has an opponent")
+)
+
+// Duel is a race of two players on the same secret word. Each player plays an own game bound to the duel.
+type Duel struct {
+	bun.BaseModel `bun:"table:duels"`
+
+	Id         int64         `bun:"id,pk,autoincrement"`
+	InviteCode string        `bun:"invite_code,notnull,unique"`
+	WordId     int           `bun:"word_id,notnull"`
+	CreatorId  int64         `bun:"creator_id,notnull"`
+	OpponentId sql.NullInt64 `bun:"opponent_id"`
+	WinnerId   sql.NullInt64 `bun:"winner_id"`
+	GuessLimit int8          `bun:"guess_limit,notnull"`
+	Status     string        `bun:"status,notnull"`
+	StartedAt  bun.NullTime  `bun:"started_at"`
+	CreatedAt  time.Time     `bun:"created_at,notnull"`
+	UpdatedAt  time.Time     `bun:"updated_at,notnull"`
+
+	Word     *Word   `bun:"rel:belongs-to,join:word_id=id"`
+	Creator  *User   `bun:"rel:belongs-to,join:creator_id=id"`
+	Opponent *User   `bun:"rel:belongs-to,join:opponent_id=id"`
+	Games    []*Game `bun:"rel:has-many,join:id=duel_id"`
+}
+
+func NewDuel(word *Word, creator *User, guessLimit int8) *Duel {
+	now := time.Now()
+
+	return &Duel{
+		InviteCode: newInviteCode(),
+		WordId:     word.Id,
+		CreatorId:  creator.Id,
+		GuessLimit: guessLimit,
+		Status:     DuelStatusWaiting,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Word:       word,
+		Creator:    creator,
+	}
+}
+
+func (d *Duel) Join(opponent *User) error {
+	if d.CreatorId == opponent.Id {
+		return ErrDuelOwnInvite
+	}
+	if d.OpponentId.Valid {
+		return ErrDuelAlreadyStarted
+	}
+
+	d.OpponentId = sql.NullInt64{Int64: opponent.Id, Valid: true}
+	d.Opponent = opponent
+	d.Status = DuelStatusPlaying
+	d.StartedAt = bun.NullTime{Time: time.Now()}
+	d.UpdatedAt = d.StartedAt.Time
+
+	return nil
+}
+
+// GameOf returns the game the user plays in the duel, nil if the user does not take part in it.
+func (d *Duel) GameOf(userId int64) *Game {
+	for _, game := range d.Games {
+		if game.UserId == userId {
+			return game
+		}
+	}
+	return nil
+}
+
+// OpponentOf returns the other participant of the duel, nil while nobody has joined.
+func (d *Duel) OpponentOf(userId int64) *User {
+	if d.CreatorId == userId {
+		return d.Opponent
+	}
+	return d.Creator
+}
+
+// IsStarted tells whether the opponent has joined, the games may be played only from then on.
+func (d *Duel) IsStarted() bool {
+	return d.Status != DuelStatusWaiting
+}
+
+// Resolve finishes the duel once both games are over. The winner is whoever solved the word in fewer
+// guesses; equal guesses are tie-broken by the time spent on the game since the duel started, the same
+// for both players however long the creator has waited for the opponent. Nobody wins if neither solved it.
+func (d *Duel) Resolve() {
+	if d.Status != DuelStatusPlaying || len(d.Games) != 2 {
+		return
+	}
+	first, second := d.Games[0], d.Games[1]
+	if first.IsPlaying || second.IsPlaying {
+		return
+	}
+
+	d.Status = DuelStatusFinished
+	d.UpdatedAt = time.Now()
+
+	var winner *Game
+	switch {
+	case first.IsWon.Bool && !second.IsWon.Bool:
+		winner = first
+	case second.IsWon.Bool && !first.IsWon.Bool:
+		winner = second
+	case first.IsWon.Bool && second.IsWon.Bool:
+		if len(first.Guesses) != len(second.Guesses) {
+			winner = first
+			if len(second.Guesses) < len(first.Guesses) {
+				winner = second
+			}
+		} else if d.playTime(first) != d.playTime(second) {
+			winner = first
+			if d.playTime(second) < d.playTime(first) {
+				winner = second
+			}
+		}
+	}
+	if winner != nil {
+		d.WinnerId = sql.NullInt64{Int64: winner.UserId, Valid: true}
+	}
+}
+
+func (d *Duel) playTime(game *Game) time.Duration {
+	return game.UpdatedAt.Sub(d.StartedAt.Time)
+}
+
+func newInviteCode() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	for i := range b {
+		b[i] = inviteCodeAlphabet[int(b[i])%len(inviteCodeAlphabet)]
+	}
+	return string(b)
+}
@@ -0,0 +1,48 @@
+package entity
+
+type LetterState string
+
+const (
+	LetterStateCorrect LetterState = "correct"
+	LetterStatePresent LetterState = "present"
+	LetterStateAbsent  LetterState = "absent"
+)
+
+type LetterResult struct {
+	Letter rune
+	State  LetterState
+}
+
+// Evaluate scores a guess against the secret word letter by letter.
+//
+// Exact matches are marked first. The remaining letters are then marked as present from left to right
+// while the secret still has unmatched occurrences of that letter, so a letter is never reported
+// more times than it occurs in the secret.
+func Evaluate(guess, secret []rune) []*LetterResult {
+	results := make([]*LetterResult, len(guess))
+	remaining := make(map[rune]int, len(secret))
+
+	for i, r := range guess {
+		results[i] = &LetterResult{Letter: r, State: LetterStateAbsent}
+		if i < len(secret) && secret[i] == r {
+			results[i].State = LetterStateCorrect
+		}
+	}
+	for i, r := range secret {
+		if i >= len(guess) || guess[i] != r {
+			remaining[r]++
+		}
+	}
+
+	for _, result := range results {
+		if result.State == LetterStateCorrect {
+			continue
+		}
+		if remaining[result.Letter] > 0 {
+			result.State = LetterStatePresent
+			remaining[result.Letter]--
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	const (
+		c = LetterStateCorrect
+		p = LetterStatePresent
+		a = LetterStateAbsent
+	)
+
+	tests := []struct {
+		name   string
+		guess  string
+		secret string
+		want   []LetterState
+	}{
+		{
+			name:   "all letters correct",
+			guess:  "город",
+			secret: "город",
+			want:   []LetterState{c, c, c, c, c},
+		},
+		{
+			name:   "repeated letter of the guess matching both occurrences in the secret",
+			guess:  "молот",
+			secret: "город",
+			want:   []LetterState{a, c, a, c, a},
+		},
+		{
+			name:   "repeated letter of the guess once in the secret: one present, one absent",
+			guess:  "какао",
+			secret: "книга",
+			want:   []LetterState{c, p, a, a, a},
+		},
+		{
+			name:   "exact match consuming the only occurrence before a misplaced one",
+			guess:  "сорок",
+			secret: "пирог",
+			want:   []LetterState{a, a, c, c, a},
+		},
+		{
+			name:   "letter repeated in the secret, guessed once in place and once misplaced",
+			guess:  "облако",
+			secret: "молоко",
+			want:   []LetterState{p, a, c, a, c, c},
+		},
+		{
+			name:   "letter repeated in the secret, guessed twice in place",
+			guess:  "трава",
+			secret: "атака",
+			want:   []LetterState{p, a, c, a, c},
+		},
+		{
+			name:   "letter repeated in the secret and in the guess, all misplaced",
+			guess:  "осока",
+			secret: "колос",
+			want:   []LetterState{p, p, p, p, a},
+		},
+		{
+			name:   "ё scored as е after normalization",
+			guess:  "ёлка",
+			secret: "елка",
+			want:   []LetterState{c, c, c, c},
+		},
+		{
+			name:   "ё in the secret matching a misplaced е",
+			guess:  "еретик",
+			secret: "берёза",
+			want:   []LetterState{p, p, p, a, a, a},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guess := []rune(normalize(t, tt.guess))
+			secret := []rune(normalize(t, tt.secret))
+
+			results := Evaluate(guess, secret)
+			got := make([]LetterState, 0, len(results))
+			for i, result := range results {
+				if result.Letter != guess[i] {
+					t.Errorf("letter %d = %c, want %c", i, result.Letter, guess[i])
+				}
+				got = append(got, result.State)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Evaluate(%s, %s) = %v, want %v", tt.guess, tt.secret, got, tt.want)
+			}
+		})
+	}
+}
+
+func normalize(t *testing.T, word string) string {
+	t.Helper()
+	normalized, err := NormalizeWord(word)
+	if err != nil {
+		t.Fatalf("NormalizeWord(%s): %v", word, err)
+	}
+	return normalized
+}
@@ -0,0 +1,88 @@
+package entity
+
+import "time"
+
+const (
+	EventGuessAccepted     = "guess_accepted"
+	EventGameFinished      = "game_finished"
+	EventDuelOpponentMoved = "duel_opponent_moved"
+	EventDailyRollover     = "daily_rollover"
+)
+
+// Event is a notification pushed to connected clients. An event without recipients is delivered to everyone.
+type Event struct {
+	Type       string         `json:"type"`
+	Recipients []int64        `json:"recipients,omitempty"`
+	Data       map[string]any `json:"data"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+func NewEvent(eventType string, data map[string]any, recipients ...int64) *Event {
+	return &Event{
+		Type:       eventType,
+		Recipients: recipients,
+		Data:       data,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// IsFor tells whether the event should be delivered to the user.
+func (e *Event) IsFor(userId int64) bool {
+	if len(e.Recipients) == 0 {
+		return true
+	}
+	for _, recipient := range e.Recipients {
+		if recipient == userId {
+			return true
+		}
+	}
+	return false
+}
+
+// NewGameEvents describes a move in a game: the accepted guess and, if the move ended the game, its result.
+func NewGameEvents(game *Game) []*Event {
+	data := map[string]any{
+		"game_id":    game.Id,
+		"mode":       game.Mode,
+		"guesses":    len(game.Guesses),
+		"is_playing": game.IsPlaying,
+	}
+	if game.DuelId.Valid {
+		data["duel_id"] = game.DuelId.Int64
+	}
+
+	events := []*Event{NewEvent(EventGuessAccepted, data, game.UserId)}
+	if !game.IsPlaying {
+		events = append(events, NewGameFinishedEvent(game))
+	}
+
+	return events
+}
+
+func NewGameFinishedEvent(game *Game) *Event {
+	return NewEvent(EventGameFinished, map[string]any{
+		"game_id": game.Id,
+		"mode":    game.Mode,
+		"is_won":  game.IsWon.Bool,
+		"word":    game.Word.Word,
+	}, game.UserId)
+}
+
+// NewDuelOpponentMovedEvent tells the opponent about a move in a duel, revealing the letter states only.
+func NewDuelOpponentMovedEvent(game *Game, opponentId int64) *Event {
+	board := game.Board()
+	states := make([]string, 0)
+	if len(board) > 0 {
+		for _, result := range board[len(board)-1] {
+			states = append(states, string(result.State))
+		}
+	}
+
+	return NewEvent(EventDuelOpponentMoved, map[string]any{
+		"duel_id":    game.DuelId.Int64,
+		"states":     states,
+		"guesses":    len(game.Guesses),
+		"is_playing": game.IsPlaying,
+		"is_won":     game.IsWon.Bool,
+	}, opponentId)
+}
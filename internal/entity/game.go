@@ -2,71 +2,144 @@ package entity
 
 import (
 	"database/sql"
+	"fmt"
 	"github.com/uptrace/bun"
 	"time"
 )
 
-type Word struct {
-	bun.BaseModel `bun:"table:words"`
+type Guess struct {
+	bun.BaseModel `bun:"table:guesses"`
 
-	Id        int       `bun:"id,pk,autoincrement"`
-	Word      string    `bun:"word,notnull"`
+	Id        int64     `bun:"id,pk,autoincrement"`
+	GameId    int64     `bun:"game_id,notnull"`
+	WordId    int       `bun:"word_id,notnull"`
 	CreatedAt time.Time `bun:"created_at,notnull"`
-}
 
-func NewWord(word string) *Word {
-	return &Word{
-		Word:      word,
-		CreatedAt: time.Now(),
-	}
+	Word *Word `bun:"rel:belongs-to,join:word_id=id"`
 }
 
-func (w *Word) AsRunes() []rune {
-	return []rune(w.Word)
+// Evaluate scores the guess against the secret word of its game.
+func (g *Guess) Evaluate(secret *Word) []*LetterResult {
+	return Evaluate(g.Word.AsRunes(), secret.AsRunes())
 }
 
-type Guess struct {
-	bun.BaseModel `bun:"table:guesses"`
+type DailyPuzzle struct {
+	bun.BaseModel `bun:"table:daily_puzzles"`
 
 	Id        int64     `bun:"id,pk,autoincrement"`
-	GameId    int64     `bun:"game_id,notnull"`
+	Date      time.Time `bun:"date,notnull,type:date"`
 	WordId    int       `bun:"word_id,notnull"`
 	CreatedAt time.Time `bun:"created_at,notnull"`
 
 	Word *Word `bun:"rel:belongs-to,join:word_id=id"`
 }
 
+func NewDailyPuzzle(date time.Time, word *Word) *DailyPuzzle {
+	return &DailyPuzzle{
+		Date:      date,
+		WordId:    word.Id,
+		CreatedAt: time.Now(),
+	}
+}
+
+const (
+	GameModePractice = "practice"
+	GameModeDaily    = "daily"
+	GameModeDuel     = "duel"
+)
+
 type Game struct {
 	bun.BaseModel `bun:"table:games"`
 
-	Id         int64        `bun:"id,pk,autoincrement"`
-	UserId     int64        `bun:"user_id,notnull"`
-	WordId     int          `bun:"word_id,notnull"`
-	GuessLimit int8         `bun:"guess_limit,notnull"`
-	IsPlaying  bool         `bun:"is_playing,notnull,default:true"`
-	IsWon      sql.NullBool `bun:"is_won"`
-	CreatedAt  time.Time    `bun:"created_at,notnull"`
-	UpdatedAt  time.Time    `bun:"updated_at,notnull"`
-
-	Guesses []*Guess `bun:"rel:has-many,join:id=game_id"`
-	Word    *Word    `bun:"rel:belongs-to,join:word_id=id"`
+	Id            int64         `bun:"id,pk,autoincrement"`
+	UserId        int64         `bun:"user_id,notnull"`
+	WordId        int           `bun:"word_id,notnull"`
+	Mode          string        `bun:"mode,notnull"`
+	DailyPuzzleId sql.NullInt64 `bun:"daily_puzzle_id"`
+	DuelId        sql.NullInt64 `bun:"duel_id"`
+	GuessLimit    int8          `bun:"guess_limit,notnull"`
+	IsHardMode    bool          `bun:"is_hard_mode,notnull,default:false"`
+	IsPlaying     bool          `bun:"is_playing,notnull,default:true"`
+	IsWon         sql.NullBool  `bun:"is_won"`
+	CreatedAt     time.Time     `bun:"created_at,notnull"`
+	UpdatedAt     time.Time     `bun:"updated_at,notnull"`
+
+	Guesses     []*Guess     `bun:"rel:has-many,join:id=game_id"`
+	Word        *Word        `bun:"rel:belongs-to,join:word_id=id"`
+	DailyPuzzle *DailyPuzzle `bun:"rel:belongs-to,join:daily_puzzle_id=id"`
+}
+
+const defaultGuessLimit = 6
+
+// GameRules are the word length and the number of guesses the games of a mode are played with.
+type GameRules struct {
+	WordLength int
+	GuessLimit int8
 }
 
-func NewGame(word *Word, currentUser *User) *Game {
+// GameOptions holds the settings a game starts with: the ones a player chooses and the ones its mode dictates.
+type GameOptions struct {
+	IsHardMode bool
+	WordLength int
+	GuessLimit int8
+}
+
+// WordLengthError is returned when a guess is not as long as the secret word of the game.
+type WordLengthError struct {
+	Length int
+}
+
+func (e *WordLengthError) Error() string {
+	return fmt.Sprintf("the word must consist of exactly %d letters", e.Length)
+}
+
+func NewGame(word *Word, currentUser *User, options GameOptions) *Game {
 	now := time.Now()
-	const guessLimit = 6
+	guessLimit := options.GuessLimit
+	if guessLimit == 0 {
+		guessLimit = defaultGuessLimit
+	}
 
 	return &Game{
 		UserId:     currentUser.Id,
 		WordId:     word.Id,
+		Word:       word,
+		Mode:       GameModePractice,
 		GuessLimit: guessLimit,
+		IsHardMode: options.IsHardMode,
 		IsPlaying:  true,
 		CreatedAt:  now,
 		UpdatedAt:  now,
 	}
 }
 
-func (g *Game) AddGuess(word *Word) *Guess {
+func NewDailyGame(puzzle *DailyPuzzle, currentUser *User, options GameOptions) *Game {
+	game := NewGame(puzzle.Word, currentUser, options)
+	game.Mode = GameModeDaily
+	game.DailyPuzzleId = sql.NullInt64{Int64: puzzle.Id, Valid: true}
+	game.DailyPuzzle = puzzle
+
+	return game
+}
+
+func NewDuelGame(duel *Duel, currentUser *User) *Game {
+	game := NewGame(duel.Word, currentUser, GameOptions{GuessLimit: duel.GuessLimit})
+	game.Mode = GameModeDuel
+	game.DuelId = sql.NullInt64{Int64: duel.Id, Valid: true}
+
+	return game
+}
+
+func (g *Game) AddGuess(word *Word) (*Guess, error) {
+	if word.Length != g.Word.Length {
+		return nil, &WordLengthError{Length: g.Word.Length}
+	}
+	if g.IsHardMode {
+		if err := g.checkHardModeConstraints(word); err != nil {
+			return nil, err
+		}
+	}
+
 	guess := &Guess{
 		GameId:    g.Id,
 		CreatedAt: time.Now(),
@@ -74,6 +147,7 @@ func (g *Game) AddGuess(word *Word) *Guess {
 		Word:      word,
 	}
 	g.Guesses = append(g.Guesses, guess)
+	g.UpdatedAt = guess.CreatedAt
 
 	if guess.WordId == g.WordId {
 		g.IsPlaying = false
@@ -85,5 +159,22 @@ func (g *Game) AddGuess(word *Word) *Guess {
 		g.IsWon.Valid = true
 	}
 
-	return guess
+	return guess, nil
+}
+
+// Surrender finishes the game as lost.
+func (g *Game) Surrender() {
+	g.IsPlaying = false
+	g.IsWon = sql.NullBool{Bool: false, Valid: true}
+	g.UpdatedAt = time.Now()
+}
+
+// Board returns the evaluation of every guess made in the game so far.
+func (g *Game) Board() [][]*LetterResult {
+	board := make([][]*LetterResult, 0, len(g.Guesses))
+	for _, guess := range g.Guesses {
+		board = append(board, guess.Evaluate(g.Word))
+	}
+
+	return board
 }
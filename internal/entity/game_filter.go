@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+// GameFilter narrows down the game history of a user. Zero values mean "no restriction".
+type GameFilter struct {
+	IsPlaying sql.NullBool
+	IsWon     sql.NullBool
+	Mode      string
+	From      time.Time
+	To        time.Time
+}
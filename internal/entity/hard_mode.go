@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HardModeError is returned when a guess in hard mode ignores hints revealed by previous guesses.
+type HardModeError struct {
+	Violations []string
+}
+
+func (e *HardModeError) Error() string {
+	return "hard mode: " + strings.Join(e.Violations, "; ")
+}
+
+// checkHardModeConstraints verifies that the word keeps every revealed green letter in place and
+// contains every revealed yellow letter at least as many times as it was revealed in a single guess.
+func (g *Game) checkHardModeConstraints(word *Word) error {
+	guessRunes := word.AsRunes()
+	fixed := make(map[int]rune)
+	required := make(map[rune]int)
+	var requiredOrder []rune
+
+	for _, results := range g.Board() {
+		found := make(map[rune]int)
+		for pos, result := range results {
+			switch result.State {
+			case LetterStateCorrect:
+				fixed[pos] = result.Letter
+				found[result.Letter]++
+			case LetterStatePresent:
+				found[result.Letter]++
+			}
+		}
+		for _, result := range results {
+			if found[result.Letter] > required[result.Letter] {
+				if required[result.Letter] == 0 {
+					requiredOrder = append(requiredOrder, result.Letter)
+				}
+				required[result.Letter] = found[result.Letter]
+			}
+		}
+	}
+
+	var violations []string
+	misplaced := make(map[rune]bool)
+	for pos := 0; pos < len(guessRunes); pos++ {
+		if letter, ok := fixed[pos]; ok && guessRunes[pos] != letter {
+			misplaced[letter] = true
+			violations = append(violations, fmt.Sprintf("%s letter must be «%c»", ordinal(pos+1), letter))
+		}
+	}
+
+	counts := make(map[rune]int)
+	for _, r := range guessRunes {
+		counts[r]++
+	}
+	for _, letter := range requiredOrder {
+		if counts[letter] >= required[letter] || misplaced[letter] {
+			continue
+		}
+		if required[letter] == 1 {
+			violations = append(violations, fmt.Sprintf("guess must contain «%c»", letter))
+		} else {
+			violations = append(violations, fmt.Sprintf("guess must contain «%c» at least %d times", letter, required[letter]))
+		}
+	}
+
+	if len(violations) > 0 {
+		return &HardModeError{Violations: violations}
+	}
+
+	return nil
+}
+
+func ordinal(n int) string {
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+		return fmt.Sprintf("%dth", n)
+	case n%10 == 1:
+		return fmt.Sprintf("%dst", n)
+	case n%10 == 2:
+		return fmt.Sprintf("%dnd", n)
+	case n%10 == 3:
+		return fmt.Sprintf("%drd", n)
+	}
+	return fmt.Sprintf("%dth", n)
+}
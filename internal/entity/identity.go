@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// UserIdentity links the account of a user at an external OpenID Connect provider to the user,
+// who can log in with the provider from then on. A user may have identities at several providers.
+type UserIdentity struct {
+	bun.BaseModel `bun:"table:user_identities"`
+
+	Id          int64     `bun:"id,pk,autoincrement"`
+	UserId      int64     `bun:"user_id,notnull"`
+	Provider    string    `bun:"provider,notnull"`
+	Subject     string    `bun:"subject,notnull"`
+	Email       string    `bun:"email,nullzero"`
+	CreatedAt   time.Time `bun:"created_at,notnull"`
+	LastLoginAt time.Time `bun:"last_login_at,notnull"`
+
+	User *User `bun:"rel:belongs-to,join:user_id=id"`
+}
+
+func NewUserIdentity(user *User, provider string, subject string, email string) *UserIdentity {
+	now := time.Now()
+
+	return &UserIdentity{
+		UserId:      user.Id,
+		Provider:    provider,
+		Subject:     subject,
+		Email:       email,
+		CreatedAt:   now,
+		LastLoginAt: now,
+		User:        user,
+	}
+}
+
+func (i *UserIdentity) MarkLogin() {
+	i.LastLoginAt = time.Now()
+}
+
+// ExternalLogin is a login started at an external provider and awaiting the user to come back.
+// Only the hash of the state handed out is stored, along with the PKCE code verifier
+// and the nonce the ID token has to carry.
+type ExternalLogin struct {
+	bun.BaseModel `bun:"table:external_logins"`
+
+	StateHash    string       `bun:"state_hash,pk"`
+	Provider     string       `bun:"provider,notnull"`
+	CodeVerifier string       `bun:"code_verifier,notnull"`
+	Nonce        string       `bun:"nonce,notnull"`
+	ExpiresAt    time.Time    `bun:"expires_at,notnull"`
+	UsedAt       bun.NullTime `bun:"used_at"`
+	CreatedAt    time.Time    `bun:"created_at,notnull"`
+}
+
+// NewExternalLogin returns the login to store and the raw state to send to the provider.
+func NewExternalLogin(provider string, codeVerifier string, nonce string, ttl time.Duration) (*ExternalLogin, string) {
+	raw := randomToken(32)
+	now := time.Now()
+
+	return &ExternalLogin{
+		StateHash:    HashLoginState(raw),
+		Provider:     provider,
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		ExpiresAt:    now.Add(ttl),
+		CreatedAt:    now,
+	}, raw
+}
+
+func (l *ExternalLogin) IsExpired() bool {
+	return !time.Now().Before(l.ExpiresAt)
+}
+
+// HashLoginState is how the raw state of an external login is looked up in the storage.
+func HashLoginState(raw string) string {
+	return HashRefreshToken(raw)
+}
@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+const (
+	LeaderboardPeriodAll   = "all"
+	LeaderboardPeriodMonth = "month"
+	LeaderboardPeriodWeek  = "week"
+)
+
+var LeaderboardPeriods = []string{LeaderboardPeriodAll, LeaderboardPeriodMonth, LeaderboardPeriodWeek}
+
+const (
+	LeaderboardKindWins       = "wins"
+	LeaderboardKindAvgGuesses = "avg_guesses"
+	LeaderboardKindStreak     = "streak"
+	LeaderboardKindDaily      = "daily"
+)
+
+// LeaderboardPeriodStart returns the first day of the period the moment belongs to, by the calendar
+// of the location, so that the periods change along with the daily puzzle.
+// Weeks start on Monday, the all-time period starts at the Unix epoch.
+func LeaderboardPeriodStart(period string, t time.Time, location *time.Location) time.Time {
+	t = t.In(location)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch period {
+	case LeaderboardPeriodMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	case LeaderboardPeriodWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return time.Unix(0, 0).UTC()
+}
+
+// LeaderboardEntry holds the counters of a user within a single leaderboard period.
+type LeaderboardEntry struct {
+	bun.BaseModel `bun:"table:leaderboard_entries"`
+
+	Period        string    `bun:"period,pk"`
+	PeriodStart   time.Time `bun:"period_start,pk,type:date"`
+	UserId        int64     `bun:"user_id,pk"`
+	Wins          int       `bun:"wins,notnull"`
+	WinGuesses    int       `bun:"win_guesses,notnull"`
+	CurrentStreak int       `bun:"current_streak,notnull"`
+	MaxStreak     int       `bun:"max_streak,notnull"`
+	UpdatedAt     time.Time `bun:"updated_at,notnull"`
+}
+
+func NewLeaderboardEntry(period string, game *Game, location *time.Location) *LeaderboardEntry {
+	return &LeaderboardEntry{
+		Period:      period,
+		PeriodStart: LeaderboardPeriodStart(period, game.UpdatedAt, location),
+		UserId:      game.UserId,
+		UpdatedAt:   time.Now(),
+	}
+}
+
+// RecordGame adds a finished game to the counters. Games must be recorded in the order they finished.
+func (e *LeaderboardEntry) RecordGame(game *Game) {
+	e.UpdatedAt = time.Now()
+
+	if !game.IsWon.Bool {
+		e.CurrentStreak = 0
+		return
+	}
+
+	e.Wins++
+	e.WinGuesses += len(game.Guesses)
+	e.CurrentStreak++
+	e.MaxStreak = max(e.MaxStreak, e.CurrentStreak)
+}
+
+// DailyResult is the outcome of a finished daily puzzle game, kept for per-puzzle rankings.
+type DailyResult struct {
+	bun.BaseModel `bun:"table:daily_results"`
+
+	DailyPuzzleId int64     `bun:"daily_puzzle_id,pk"`
+	UserId        int64     `bun:"user_id,pk"`
+	IsWon         bool      `bun:"is_won,notnull"`
+	Guesses       int       `bun:"guesses,notnull"`
+	SolveSeconds  int       `bun:"solve_seconds,notnull"`
+	CreatedAt     time.Time `bun:"created_at,notnull"`
+}
+
+func NewDailyResult(game *Game) *DailyResult {
+	return &DailyResult{
+		DailyPuzzleId: game.DailyPuzzleId.Int64,
+		UserId:        game.UserId,
+		IsWon:         game.IsWon.Bool,
+		Guesses:       len(game.Guesses),
+		SolveSeconds:  int(game.UpdatedAt.Sub(game.CreatedAt).Seconds()),
+		CreatedAt:     time.Now(),
+	}
+}
+
+// Ranking is a single row of a leaderboard. SolveSeconds is only filled in for daily puzzle rankings.
+type Ranking struct {
+	Rank         int     `bun:"rank"`
+	UserId       int64   `bun:"user_id"`
+	Name         string  `bun:"name"`
+	Score        float64 `bun:"score"`
+	SolveSeconds int     `bun:"solve_seconds"`
+}
@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+// LoginLockout is how an account is locked out after repeated failed logins: once Threshold attempts
+// fail in a row, every further failure locks it for Duration, doubled each time up to MaxDuration.
+// A zero Threshold disables the lockout.
+type LoginLockout struct {
+	Threshold   int
+	Duration    time.Duration
+	MaxDuration time.Duration
+}
+
+// LockedFor returns how long the account stays locked out, zero when logins are allowed.
+func (user *User) LockedFor() time.Duration {
+	if user.LockedUntil.IsZero() {
+		return 0
+	}
+
+	return max(time.Until(user.LockedUntil.Time), 0)
+}
+
+// LockOut locks the account out when the failed logins counted so far reach the threshold of the policy.
+// It reports whether the account got locked. The failed logins are counted by the storage, so that
+// concurrent attempts are all counted.
+func (user *User) LockOut(lockout LoginLockout) bool {
+	if lockout.Threshold <= 0 || user.FailedLogins < lockout.Threshold {
+		return false
+	}
+
+	duration := lockout.Duration
+	for i := lockout.Threshold; i < user.FailedLogins && duration < lockout.MaxDuration; i++ {
+		duration *= 2
+	}
+	user.LockedUntil.Time = time.Now().Add(min(duration, lockout.MaxDuration))
+
+	return true
+}
+
+// ResetLoginFailures forgets the failed attempts and lifts the lockout.
+func (user *User) ResetLoginFailures() {
+	user.FailedLogins = 0
+	user.LockedUntil.Time = time.Time{}
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrNotRussianWord    = errors.New("not a Russian word")
+	ErrHyphenatedWord    = errors.New("hyphenated words are not playable")
+	ErrUnsupportedLength = fmt.Errorf("only words of %d to %d letters are playable", MinWordLength, MaxWordLength)
+)
+
+// latinHomoglyphs maps Latin letters to the Cyrillic ones they are indistinguishable from,
+// which sneak into word lists typed on a mixed keyboard layout or recognised from scans.
+var latinHomoglyphs = map[rune]rune{
+	'a': 'а', 'b': 'в', 'c': 'с', 'e': 'е', 'h': 'н', 'k': 'к', 'm': 'м',
+	'o': 'о', 'p': 'р', 't': 'т', 'x': 'х', 'y': 'у',
+}
+
+const (
+	hyphens        = "-\u2010\u2011\u2012\u2013\u2014\u2212"
+	invisibleRunes = "\u00ad\u200b\u200c\u200d\ufeff"
+)
+
+// NormalizeWord brings a word to the spelling the dictionary stores: lower case, "е" instead of "ё",
+// no invisible characters and Cyrillic letters in place of the Latin look-alikes.
+// It fails for words which cannot be played whatever their spelling is.
+func NormalizeWord(raw string) (string, error) {
+	var b strings.Builder
+	hasCyrillic := false
+	for _, r := range strings.ToLower(strings.TrimSpace(raw)) {
+		switch {
+		case strings.ContainsRune(invisibleRunes, r):
+			continue
+		case strings.ContainsRune(hyphens, r):
+			return "", ErrHyphenatedWord
+		case r == 'ё':
+			r = 'е'
+			hasCyrillic = true
+		case unicode.Is(unicode.Latin, r):
+			if cyrillic, ok := latinHomoglyphs[r]; ok {
+				r = cyrillic
+			}
+		default:
+			hasCyrillic = true
+		}
+		if r < 'а' || r > 'я' {
+			return "", ErrNotRussianWord
+		}
+		b.WriteRune(r)
+	}
+
+	// A word spelled in Latin letters only is not a misprint of a Russian one.
+	if !hasCyrillic {
+		return "", ErrNotRussianWord
+	}
+	word := b.String()
+	if length := utf8.RuneCountInString(word); length < MinWordLength || length > MaxWordLength {
+		return "", ErrUnsupportedLength
+	}
+
+	return word, nil
+}
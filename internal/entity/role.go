@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+const (
+	RolePlayer    = "player"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Roles are ordered by privilege, every role is granted what the roles before it are.
+var Roles = []string{RolePlayer, RoleModerator, RoleAdmin}
+
+var (
+	ErrUnknownRole       = errors.New("unknown role")
+	ErrUserAlreadyBanned = errors.New("user is already banned")
+	ErrUserNotBanned     = errors.New("user is not banned")
+)
+
+// HasRole tells whether the user is granted the privileges of the role.
+func (user *User) HasRole(role string) bool {
+	return slices.Index(Roles, user.Role) >= slices.Index(Roles, role) && slices.Contains(Roles, role)
+}
+
+func (user *User) SetRole(role string) error {
+	if !slices.Contains(Roles, role) {
+		return ErrUnknownRole
+	}
+	user.Role = role
+	user.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (user *User) IsBanned() bool {
+	return !user.BannedAt.IsZero()
+}
+
+func (user *User) Ban(reason string) error {
+	if user.IsBanned() {
+		return ErrUserAlreadyBanned
+	}
+	now := time.Now()
+	user.BannedAt.Time = now
+	user.BanReason = reason
+	user.UpdatedAt = now
+
+	return nil
+}
+
+func (user *User) Unban() error {
+	if !user.IsBanned() {
+		return ErrUserNotBanned
+	}
+	user.BannedAt.Time = time.Time{}
+	user.BanReason = ""
+	user.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// ResetStats starts the statistics of the user over: games finished before are no longer counted.
+func (user *User) ResetStats() {
+	now := time.Now()
+	user.StatsResetAt.Time = now
+	user.UpdatedAt = now
+}
@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// Session is a login of a user on one device. The refresh tokens rotated within it form a family:
+// revoking the session logs out every access and refresh token it has issued.
+type Session struct {
+	bun.BaseModel `bun:"table:sessions"`
+
+	Id        string       `bun:"id,pk"`
+	UserId    int64        `bun:"user_id,notnull"`
+	CreatedAt time.Time    `bun:"created_at,notnull"`
+	RevokedAt bun.NullTime `bun:"revoked_at"`
+
+	User *User `bun:"rel:belongs-to,join:user_id=id"`
+}
+
+func NewSession(user *User) *Session {
+	return &Session{
+		Id:        randomToken(16),
+		UserId:    user.Id,
+		CreatedAt: time.Now(),
+		User:      user,
+	}
+}
+
+func (s *Session) IsRevoked() bool {
+	return !s.RevokedAt.IsZero()
+}
+
+// RefreshToken is a single-use token exchanging for a new access and refresh token pair.
+// Only the hash of the token is stored, together with the access token issued alongside it,
+// so that the access token can be revoked when its session ends.
+type RefreshToken struct {
+	bun.BaseModel `bun:"table:refresh_tokens"`
+
+	Id              int64        `bun:"id,pk,autoincrement"`
+	SessionId       string       `bun:"session_id,notnull"`
+	TokenHash       string       `bun:"token_hash,notnull,unique"`
+	AccessJti       string       `bun:"access_jti,notnull"`
+	AccessExpiresAt time.Time    `bun:"access_expires_at,notnull"`
+	ExpiresAt       time.Time    `bun:"expires_at,notnull"`
+	UsedAt          bun.NullTime `bun:"used_at"`
+	CreatedAt       time.Time    `bun:"created_at,notnull"`
+
+	Session *Session `bun:"rel:belongs-to,join:session_id=id"`
+}
+
+// NewRefreshToken returns the token to store and the raw token to hand out to the client.
+func NewRefreshToken(
+	session *Session,
+	accessJti string,
+	accessExpiresAt time.Time,
+	expiresAt time.Time,
+) (*RefreshToken, string) {
+	raw := randomToken(32)
+
+	return &RefreshToken{
+		SessionId:       session.Id,
+		TokenHash:       HashRefreshToken(raw),
+		AccessJti:       accessJti,
+		AccessExpiresAt: accessExpiresAt,
+		ExpiresAt:       expiresAt,
+		CreatedAt:       time.Now(),
+		Session:         session,
+	}, raw
+}
+
+func (t *RefreshToken) IsExpired() bool {
+	return !time.Now().Before(t.ExpiresAt)
+}
+
+// RevokedToken is an access token rejected before it expires, e.g. after a logout.
+type RevokedToken struct {
+	bun.BaseModel `bun:"table:revoked_tokens"`
+
+	Jti       string    `bun:"jti,pk"`
+	ExpiresAt time.Time `bun:"expires_at,notnull"`
+	RevokedAt time.Time `bun:"revoked_at,notnull"`
+}
+
+// HashRefreshToken is how a raw refresh token is looked up in the storage.
+func HashRefreshToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewTokenId returns a random identifier for the jti claim of an access token.
+func NewTokenId() string {
+	return randomToken(16)
+}
+
+func randomToken(size int) string {
+	b := make([]byte, size)
+	_, _ = rand.Read(b)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
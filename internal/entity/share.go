@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/uptrace/bun"
+	"strings"
+	"time"
+)
+
+const shareTitle = "Вордка"
+
+var shareSquares = map[LetterState]string{
+	LetterStateCorrect: "🟩",
+	LetterStatePresent: "🟨",
+	LetterStateAbsent:  "⬜",
+}
+
+// ShareToken gives public, spoiler-free access to the result of a finished game until it is revoked.
+type ShareToken struct {
+	bun.BaseModel `bun:"table:share_tokens"`
+
+	Id        int64        `bun:"id,pk,autoincrement"`
+	GameId    int64        `bun:"game_id,notnull"`
+	Token     string       `bun:"token,notnull,unique"`
+	CreatedAt time.Time    `bun:"created_at,notnull"`
+	RevokedAt bun.NullTime `bun:"revoked_at"`
+
+	Game *Game `bun:"rel:belongs-to,join:game_id=id"`
+}
+
+func NewShareToken(game *Game) *ShareToken {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return &ShareToken{
+		GameId:    game.Id,
+		Token:     base64.RawURLEncoding.EncodeToString(b),
+		CreatedAt: time.Now(),
+		Game:      game,
+	}
+}
+
+// ShareHeader is the first line of a shared result, e.g. "Вордка #123 4/6*".
+// Daily games are numbered by their puzzle, other games by themselves; a star marks hard mode.
+func (g *Game) ShareHeader() string {
+	number := g.Id
+	if g.DailyPuzzleId.Valid {
+		number = g.DailyPuzzleId.Int64
+	}
+
+	score := "X"
+	if g.IsWon.Bool {
+		score = fmt.Sprint(len(g.Guesses))
+	}
+
+	header := fmt.Sprintf("%s #%d %s/%d", shareTitle, number, score, g.GuessLimit)
+	if g.IsHardMode {
+		header += "*"
+	}
+
+	return header
+}
+
+// ShareGrid renders the board as rows of colored squares without revealing any letter.
+func (g *Game) ShareGrid() string {
+	rows := make([]string, 0, len(g.Guesses))
+	for _, results := range g.Board() {
+		var row strings.Builder
+		for _, result := range results {
+			row.WriteString(shareSquares[result.State])
+		}
+		rows = append(rows, row.String())
+	}
+
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"github.com/uptrace/bun"
+	"math"
+	"time"
+)
+
+const defaultGuessDistributionSize = 6
+
+// UserStats is an aggregate of all finished games of a user, updated every time a game finishes.
+type UserStats struct {
+	bun.BaseModel `bun:"table:user_stats"`
+
+	UserId            int64     `bun:"user_id,pk"`
+	GamesPlayed       int       `bun:"games_played,notnull"`
+	GamesWon          int       `bun:"games_won,notnull"`
+	HardModeWins      int       `bun:"hard_mode_wins,notnull"`
+	CurrentStreak     int       `bun:"current_streak,notnull"`
+	MaxStreak         int       `bun:"max_streak,notnull"`
+	GuessDistribution []int     `bun:"guess_distribution,array,notnull"`
+	UpdatedAt         time.Time `bun:"updated_at,notnull"`
+}
+
+func NewUserStats(userId int64) *UserStats {
+	return &UserStats{
+		UserId:            userId,
+		GuessDistribution: make([]int, defaultGuessDistributionSize),
+		UpdatedAt:         time.Now(),
+	}
+}
+
+// RecordGame adds a finished game to the aggregate. Games must be recorded in the order they finished.
+func (s *UserStats) RecordGame(game *Game) {
+	s.GamesPlayed++
+	s.UpdatedAt = time.Now()
+
+	if !game.IsWon.Bool {
+		s.CurrentStreak = 0
+		return
+	}
+
+	s.GamesWon++
+	if game.IsHardMode {
+		s.HardModeWins++
+	}
+	s.CurrentStreak++
+	s.MaxStreak = max(s.MaxStreak, s.CurrentStreak)
+
+	guesses := len(game.Guesses)
+	for len(s.GuessDistribution) < guesses {
+		s.GuessDistribution = append(s.GuessDistribution, 0)
+	}
+	s.GuessDistribution[guesses-1]++
+}
+
+func (s *UserStats) WinPercentage() int {
+	if s.GamesPlayed == 0 {
+		return 0
+	}
+
+	return int(math.Round(float64(s.GamesWon) * 100 / float64(s.GamesPlayed)))
+}
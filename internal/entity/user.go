@@ -1,11 +1,34 @@
 package entity
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"github.com/uptrace/bun"
 	"golang.org/x/crypto/bcrypt"
+	"slices"
+	"sync"
 	"time"
 )
 
+// passwordCost is the bcrypt cost of the password hashes.
+const passwordCost = 12
+
+const (
+	DefaultLocale   = "ru"
+	DefaultTimezone = "Europe/Moscow"
+	deletedUserName = "Удалённый игрок"
+)
+
+// Locales are the languages the users can choose from.
+var Locales = []string{"ru", "en"}
+
+var (
+	ErrUnsupportedLocale = errors.New("unsupported locale")
+	ErrUnknownTimezone   = errors.New("unknown timezone")
+)
+
 type User struct {
 	bun.BaseModel `bun:"table:users"`
 
@@ -13,9 +36,21 @@ type User struct {
 	Name            string       `bun:"name,notnull"`
 	Email           string       `bun:"email,notnull,unique"`
 	EmailVerifiedAt bun.NullTime `bun:"email_verified_at"`
-	Password        string       `bun:"password,notnull"`
+	Password        string       `bun:"password,nullzero"`
 	CreatedAt       time.Time    `bun:"created_at,notnull"`
 	UpdatedAt       time.Time    `bun:"updated_at,notnull"`
+
+	EmailVerificationSentAt bun.NullTime `bun:"email_verification_sent_at"`
+	Locale                  string       `bun:"locale,notnull"`
+	Timezone                string       `bun:"timezone,notnull"`
+	DeletedAt               bun.NullTime `bun:"deleted_at"`
+	Role                    string       `bun:"role,notnull"`
+	BannedAt                bun.NullTime `bun:"banned_at"`
+	BanReason               string       `bun:"ban_reason,nullzero"`
+	StatsResetAt            bun.NullTime `bun:"stats_reset_at"`
+	FailedLogins            int          `bun:"failed_logins,notnull"`
+	LockedUntil             bun.NullTime `bun:"locked_until"`
+	SessionsRevokedAt       bun.NullTime `bun:"sessions_revoked_at"`
 }
 
 func NewUser(name string, email string, rawPassword string) (*User, error) {
@@ -29,17 +64,179 @@ func NewUser(name string, email string, rawPassword string) (*User, error) {
 		Name:      name,
 		Email:     email,
 		Password:  password,
+		Locale:    DefaultLocale,
+		Timezone:  DefaultTimezone,
+		Role:      RolePlayer,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}, nil
 }
 
+// NewExternalUser creates a user logging in with an external provider only, without a password.
+// The email is verified already when the provider says so.
+func NewExternalUser(name string, email string, isEmailVerified bool) *User {
+	now := time.Now()
+	user := &User{
+		Name:      name,
+		Email:     email,
+		Locale:    DefaultLocale,
+		Timezone:  DefaultTimezone,
+		Role:      RolePlayer,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if isEmailVerified {
+		user.EmailVerifiedAt = bun.NullTime{Time: now}
+	}
+
+	return user
+}
+
+// HasPassword tells whether the user can log in with a password, users of external providers may have none.
+func (user *User) HasPassword() bool {
+	return user.Password != ""
+}
+
+// RemovePassword leaves the user to log in with external providers or to set a password by resetting it.
+func (user *User) RemovePassword() {
+	user.Password = ""
+	user.UpdatedAt = time.Now()
+}
+
+// ComparePassword compares a password to its bcrypt hash. Every password check goes through it,
+// so the tests can tell how much work each check takes by counting the comparisons.
+var ComparePassword = bcrypt.CompareHashAndPassword
+
+// IsPasswordMatch checks the password. A user without a password matches none, after the same work
+// as a user with one does, like MatchDummyPassword.
 func (user *User) IsPasswordMatch(password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	if user.Password == "" {
+		MatchDummyPassword(password)
+		return false
+	}
+
+	err := ComparePassword([]byte(user.Password), []byte(password))
 	return err == nil
 }
 
+// MatchDummyPassword compares the password to a hash nobody knows the password of, spending as much time
+// as checking the password of a real user. It keeps the response time from telling which users exist.
+func MatchDummyPassword(password string) {
+	_ = ComparePassword([]byte(dummyPasswordHash()), []byte(password))
+}
+
+func (user *User) ChangePassword(rawPassword string) error {
+	password, err := hashPassword(rawPassword)
+	if err != nil {
+		return err
+	}
+	user.Password = password
+	user.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// IsIssuedBeforeRevocation tells whether a token issued at the time predates the last revocation
+// of all the user's sessions. The tokens without a jti can't be revoked one by one, only this way.
+func (user *User) IsIssuedBeforeRevocation(issuedAt time.Time) bool {
+	return !user.SessionsRevokedAt.IsZero() && !issuedAt.After(user.SessionsRevokedAt.Time)
+}
+
+// PasswordStamp changes whenever the password does, without revealing the password hash.
+func (user *User) PasswordStamp() string {
+	sum := sha256.Sum256([]byte(user.Password))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (user *User) IsEmailVerified() bool {
+	return !user.EmailVerifiedAt.IsZero()
+}
+
+func (user *User) VerifyEmail() {
+	now := time.Now()
+	user.EmailVerifiedAt = bun.NullTime{Time: now}
+	user.UpdatedAt = now
+}
+
+// VerificationResendIn is how long the user has to wait before another verification email may be sent.
+func (user *User) VerificationResendIn(period time.Duration) time.Duration {
+	if user.EmailVerificationSentAt.IsZero() {
+		return 0
+	}
+	return max(time.Until(user.EmailVerificationSentAt.Add(period)), 0)
+}
+
+func (user *User) MarkVerificationSent() {
+	user.EmailVerificationSentAt = bun.NullTime{Time: time.Now()}
+}
+
+func (user *User) Rename(name string) {
+	user.Name = name
+	user.UpdatedAt = time.Now()
+}
+
+// ChangeEmail replaces the email, which has to be verified again.
+func (user *User) ChangeEmail(email string) {
+	if email == user.Email {
+		return
+	}
+	user.Email = email
+	user.EmailVerifiedAt = bun.NullTime{}
+	user.EmailVerificationSentAt = bun.NullTime{}
+	user.UpdatedAt = time.Now()
+}
+
+func (user *User) SetLocale(locale string) error {
+	if !slices.Contains(Locales, locale) {
+		return ErrUnsupportedLocale
+	}
+	user.Locale = locale
+	user.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (user *User) SetTimezone(timezone string) error {
+	if _, err := time.LoadLocation(timezone); err != nil || timezone == "" || timezone == "Local" {
+		return ErrUnknownTimezone
+	}
+	user.Timezone = timezone
+	user.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// Anonymize erases the personal data of a deleted account. Games and statistics stay,
+// as they are referenced by leaderboards, duels and puzzles of other players, but nothing
+// links them to the person any longer and the account cannot be logged in to.
+func (user *User) Anonymize() {
+	now := time.Now()
+	user.Name = deletedUserName
+	user.Email = fmt.Sprintf("deleted-%d@wordka.invalid", user.Id)
+	user.EmailVerifiedAt = bun.NullTime{}
+	user.EmailVerificationSentAt = bun.NullTime{}
+	user.Password = ""
+	user.Locale = DefaultLocale
+	user.Timezone = DefaultTimezone
+	user.Role = RolePlayer
+	user.DeletedAt = bun.NullTime{Time: now}
+	user.UpdatedAt = now
+}
+
+func (user *User) IsDeleted() bool {
+	return !user.DeletedAt.IsZero()
+}
+
 func hashPassword(rawPassword string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(rawPassword), 12)
+	bytes, err := bcrypt.GenerateFromPassword([]byte(rawPassword), passwordCost)
 	return string(bytes), err
 }
+
+// dummyPasswordHash is hashed with the cost of the real passwords, so comparing to it takes as long.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, err := hashPassword(randomToken(32))
+	if err != nil {
+		panic(err)
+	}
+	return hash
+})
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+)
+
+func TestEveryPasswordCheckComparesOneHash(t *testing.T) {
+	user, err := NewUser("Игрок", "player@example.com", "correct horse battery")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	passwordless := NewExternalUser("Игрок", "external@example.com", true)
+
+	var costs []int
+	compare := ComparePassword
+	ComparePassword = func(hash, password []byte) error {
+		cost, err := bcrypt.Cost(hash)
+		if err != nil {
+			t.Fatalf("compared to a hash which is not bcrypt: %v", err)
+		}
+		costs = append(costs, cost)
+		return compare(hash, password)
+	}
+	t.Cleanup(func() { ComparePassword = compare })
+
+	tests := []struct {
+		name  string
+		check func() bool
+		want  bool
+	}{
+		{name: "password", check: func() bool { return user.IsPasswordMatch("correct horse battery") }, want: true},
+		{name: "wrong password", check: func() bool { return user.IsPasswordMatch("wrong password") }},
+		{name: "user without a password", check: func() bool { return passwordless.IsPasswordMatch("") }},
+		{name: "dummy password", check: func() bool { MatchDummyPassword("wrong password"); return false }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			costs = costs[:0]
+			if got := tt.check(); got != tt.want {
+				t.Errorf("match = %t, want %t", got, tt.want)
+			}
+			if len(costs) != 1 || costs[0] != passwordCost {
+				t.Errorf("compared to hashes of the costs %v, want a single hash of the cost %d", costs, passwordCost)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package entity
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/uptrace/bun"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MinWordLength = 4
+	MaxWordLength = 8
+)
+
+const (
+	MinWordDifficulty = 1
+	MaxWordDifficulty = 5
+)
+
+var (
+	ErrOffensiveAnswer   = errors.New("an offensive word cannot be an answer")
+	ErrDisabledAnswer    = errors.New("a disabled word cannot be an answer")
+	ErrInvalidDifficulty = errors.New("the difficulty must be from 1 to 5")
+)
+
+// Word is an entry of the dictionary. Every enabled word is accepted as a guess,
+// while only the curated answer-eligible ones can become the secret word of a game.
+type Word struct {
+	bun.BaseModel `bun:"table:words"`
+
+	Id          int           `bun:"id,pk,autoincrement"`
+	Word        string        `bun:"word,notnull"`
+	Length      int           `bun:"length,notnull"`
+	IsEnabled   bool          `bun:"is_enabled,notnull"`
+	IsAnswer    bool          `bun:"is_answer,notnull"`
+	IsOffensive bool          `bun:"is_offensive,notnull"`
+	Difficulty  sql.NullInt16 `bun:"difficulty"`
+	CreatedAt   time.Time     `bun:"created_at,notnull"`
+
+	Sources []*WordSource `bun:"rel:has-many,join:id=word_id"`
+}
+
+// WordSource records that a word was found in a source, so the words only one source vouches for
+// can be reviewed by hand.
+type WordSource struct {
+	bun.BaseModel `bun:"table:word_sources"`
+
+	WordId      int       `bun:"word_id,pk"`
+	Source      string    `bun:"source,pk"`
+	FirstSeenAt time.Time `bun:"first_seen_at,notnull"`
+	LastSeenAt  time.Time `bun:"last_seen_at,notnull"`
+
+	Word *Word `bun:"rel:belongs-to,join:word_id=id"`
+}
+
+// NewWord creates an enabled word accepted only as a guess until it is promoted to the answers.
+func NewWord(word string) *Word {
+	return &Word{
+		Word:      word,
+		Length:    utf8.RuneCountInString(word),
+		IsEnabled: true,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (w *Word) AsRunes() []rune {
+	return []rune(w.Word)
+}
+
+// Promote makes the word eligible to be the secret word of a game.
+func (w *Word) Promote() error {
+	if w.IsOffensive {
+		return ErrOffensiveAnswer
+	}
+	if !w.IsEnabled {
+		return ErrDisabledAnswer
+	}
+	w.IsAnswer = true
+
+	return nil
+}
+
+func (w *Word) Demote() {
+	w.IsAnswer = false
+}
+
+func (w *Word) Enable() {
+	w.IsEnabled = true
+}
+
+// Disable removes the word from both the answers and the accepted guesses.
+func (w *Word) Disable() {
+	w.IsEnabled = false
+	w.IsAnswer = false
+}
+
+// MarkOffensive flags the word as offensive, which also takes it out of the answers.
+func (w *Word) MarkOffensive(isOffensive bool) {
+	w.IsOffensive = isOffensive
+	if isOffensive {
+		w.IsAnswer = false
+	}
+}
+
+// SetDifficulty rates the word from 1 to 5, or clears the rating when the difficulty is 0.
+func (w *Word) SetDifficulty(difficulty int) error {
+	if difficulty == 0 {
+		w.Difficulty = sql.NullInt16{}
+		return nil
+	}
+	if difficulty < MinWordDifficulty || difficulty > MaxWordDifficulty {
+		return ErrInvalidDifficulty
+	}
+	w.Difficulty = sql.NullInt16{Int16: int16(difficulty), Valid: true}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package dictfile
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/Markard/wordka/internal/usecase/dictionary"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// readCSV reads a list with a header row. The "word" column is required, the optional
+// "answer", "offensive" and "difficulty" columns fill in the flags of the word.
+func readCSV(r io.Reader) ([]dictionary.Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["word"]; !ok {
+		return nil, errors.New(`csv header: the "word" column is required`)
+	}
+
+	var entries []dictionary.Entry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		entries = append(entries, newCSVEntry(line, record, columns))
+	}
+
+	return entries, nil
+}
+
+func newCSVEntry(line int, record []string, columns map[string]int) dictionary.Entry {
+	value := func(column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	entry := dictionary.Entry{Line: line, Word: value("word")}
+	var err error
+	if entry.IsAnswer, err = parseFlag(value("answer")); err != nil {
+		entry.Invalid = "answer: " + err.Error()
+	} else if entry.IsOffensive, err = parseFlag(value("offensive")); err != nil {
+		entry.Invalid = "offensive: " + err.Error()
+	} else if difficulty := value("difficulty"); difficulty != "" {
+		if entry.Difficulty, err = strconv.Atoi(difficulty); err != nil {
+			entry.Invalid = "difficulty: not a number"
+		}
+	}
+
+	return entry
+}
+
+func parseFlag(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%q is neither true nor false", value)
+	}
+
+	return flag, nil
+}
@@ -0,0 +1,56 @@
+package dictfile
+
+import (
+	"bufio"
+	"github.com/Markard/wordka/internal/usecase/dictionary"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Morphological fields of a Hunspell entry describing a form other than the singular nominative.
+var hunspellIndirectForms = []string{
+	"is:pl", "is:plur", "is:gen", "is:gent", "is:dat", "is:datv", "is:acc", "is:accs",
+	"is:ins", "is:ablt", "is:prep", "is:loc", "is:loct", "is:voc", "is:voct",
+}
+
+// readHunspell reads a Hunspell .dic file annotated with morphological fields, e.g. "кошка/I po:noun",
+// and keeps the nouns. Plain .dic files without the "po:" field cannot tell a noun apart and yield nothing.
+func readHunspell(r io.Reader) ([]dictionary.Entry, error) {
+	var entries []dictionary.Entry
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		// The first line of a .dic file is the approximate number of entries.
+		if len(fields) == 0 || (line == 1 && len(fields) == 1 && isNumber(fields[0])) {
+			continue
+		}
+
+		word, _, _ := strings.Cut(fields[0], "/")
+		if isHunspellNominativeNoun(fields[1:]) && isPlayableLength(word) {
+			entries = append(entries, dictionary.Entry{Line: line, Word: word})
+		}
+	}
+
+	return entries, scanner.Err()
+}
+
+func isHunspellNominativeNoun(morphology []string) bool {
+	isNoun := false
+	for _, field := range morphology {
+		field = strings.ToLower(field)
+		if field == "po:noun" {
+			isNoun = true
+		}
+		if slices.Contains(hunspellIndirectForms, field) {
+			return false
+		}
+	}
+
+	return isNoun
+}
+
+func isNumber(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) }) == -1
+}
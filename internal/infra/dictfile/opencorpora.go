@@ -0,0 +1,87 @@
+package dictfile
+
+import (
+	"encoding/xml"
+	"errors"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/usecase/dictionary"
+	"io"
+	"slices"
+	"unicode/utf8"
+)
+
+// properNounGrammemes mark names, places, organisations and abbreviations, which are not common nouns.
+var properNounGrammemes = []string{"Name", "Surn", "Patr", "Geox", "Orgn", "Trad", "Abbr", "Init", "Erro", "Dist"}
+
+type openCorporaGrammeme struct {
+	Value string `xml:"v,attr"`
+}
+
+type openCorporaLemma struct {
+	Lemma struct {
+		Grammemes []openCorporaGrammeme `xml:"g"`
+	} `xml:"l"`
+	Forms []struct {
+		Text      string                `xml:"t,attr"`
+		Grammemes []openCorporaGrammeme `xml:"g"`
+	} `xml:"f"`
+}
+
+// readOpenCorpora streams the lemmata of an OpenCorpora XML dictionary
+// and keeps the singular nominative forms of common nouns.
+func readOpenCorpora(r io.Reader) ([]dictionary.Entry, error) {
+	var entries []dictionary.Entry
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "lemma" {
+			continue
+		}
+		line, _ := decoder.InputPos()
+		lemma := &openCorporaLemma{}
+		if err := decoder.DecodeElement(lemma, &start); err != nil {
+			return nil, err
+		}
+
+		lemmaGrammemes := grammemeValues(lemma.Lemma.Grammemes)
+		if !slices.Contains(lemmaGrammemes, "NOUN") || slices.ContainsFunc(lemmaGrammemes, isProperNounGrammeme) {
+			continue
+		}
+		for _, form := range lemma.Forms {
+			formGrammemes := grammemeValues(form.Grammemes)
+			isNominative := slices.Contains(formGrammemes, "sing") && slices.Contains(formGrammemes, "nomn")
+			if isNominative && isPlayableLength(form.Text) {
+				entries = append(entries, dictionary.Entry{Line: line, Word: form.Text})
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+func grammemeValues(grammemes []openCorporaGrammeme) []string {
+	values := make([]string, 0, len(grammemes))
+	for _, grammeme := range grammemes {
+		values = append(values, grammeme.Value)
+	}
+
+	return values
+}
+
+func isProperNounGrammeme(grammeme string) bool {
+	return slices.Contains(properNounGrammemes, grammeme)
+}
+
+func isPlayableLength(word string) bool {
+	length := utf8.RuneCountInString(word)
+
+	return length >= entity.MinWordLength && length <= entity.MaxWordLength
+}
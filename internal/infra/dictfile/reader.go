@@ -0,0 +1,53 @@
+// Package dictfile reads word lists in the formats the dictionary can be imported from.
+package dictfile
+
+import (
+	"fmt"
+	"github.com/Markard/wordka/internal/usecase/dictionary"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	FormatText        = "text"
+	FormatCSV         = "csv"
+	FormatOpenCorpora = "opencorpora"
+	FormatHunspell    = "hunspell"
+)
+
+var Formats = []string{FormatText, FormatCSV, FormatOpenCorpora, FormatHunspell}
+
+var extensions = map[string]string{
+	".txt": FormatText,
+	".csv": FormatCSV,
+	".xml": FormatOpenCorpora,
+	".dic": FormatHunspell,
+}
+
+// FormatOf guesses the format of a word list by its file extension.
+func FormatOf(path string) (string, error) {
+	format, ok := extensions[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return "", fmt.Errorf("cannot guess the format of %s, choose one of: %s", path, strings.Join(Formats, ", "))
+	}
+
+	return format, nil
+}
+
+// Read returns the entries of a word list. Morphological dictionaries are narrowed down
+// to singular nominative nouns of a playable length, other lists are returned as they are.
+func Read(format string, r io.Reader) ([]dictionary.Entry, error) {
+	switch format {
+	case FormatText:
+		return readText(r)
+	case FormatCSV:
+		return readCSV(r)
+	case FormatOpenCorpora:
+		return readOpenCorpora(r)
+	case FormatHunspell:
+		return readHunspell(r)
+	default:
+		return nil, fmt.Errorf("unknown format %q, choose one of: %s", format, strings.Join(Formats, ", "))
+	}
+}
@@ -0,0 +1,23 @@
+package dictfile
+
+import (
+	"bufio"
+	"github.com/Markard/wordka/internal/usecase/dictionary"
+	"io"
+	"strings"
+)
+
+// readText reads one word per line, skipping blank lines and "#" comments.
+func readText(r io.Reader) ([]dictionary.Entry, error) {
+	var entries []dictionary.Entry
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		entries = append(entries, dictionary.Entry{Line: line, Word: word})
+	}
+
+	return entries, scanner.Err()
+}
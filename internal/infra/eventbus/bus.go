@@ -0,0 +1,85 @@
+package eventbus
+
+import (
+	"github.com/Markard/wordka/internal/entity"
+	"log/slog"
+	"sync"
+)
+
+// subscriptionBuffer is how many undelivered events a slow subscriber may lag behind before events get dropped.
+const subscriptionBuffer = 16
+
+// Bus delivers events published by use cases to the subscribed clients.
+type Bus interface {
+	Publish(event *entity.Event)
+	// PublishLocal delivers the event to the subscribers of the current app instance only. It is meant for
+	// events every instance raises on its own, like the daily rollover, which would be duplicated otherwise.
+	PublishLocal(event *entity.Event)
+	// Subscribe returns a channel receiving the events for the user and a function closing the subscription.
+	Subscribe(userId int64) (<-chan *entity.Event, func())
+	// Close ends all subscriptions, closing their channels.
+	Close()
+}
+
+type subscription struct {
+	userId int64
+	events chan *entity.Event
+}
+
+// MemoryBus fans events out to the subscribers of the current app instance.
+type MemoryBus struct {
+	logger        *slog.Logger
+	m             sync.RWMutex
+	subscriptions map[*subscription]struct{}
+}
+
+func NewMemoryBus(logger *slog.Logger) *MemoryBus {
+	return &MemoryBus{logger: logger, subscriptions: make(map[*subscription]struct{})}
+}
+
+func (b *MemoryBus) Publish(event *entity.Event) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	for s := range b.subscriptions {
+		if !event.IsFor(s.userId) {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+			b.logger.Warn("EventBus: Subscriber is too slow, event dropped", "userId", s.userId, "type", event.Type)
+		}
+	}
+}
+
+func (b *MemoryBus) PublishLocal(event *entity.Event) {
+	b.Publish(event)
+}
+
+func (b *MemoryBus) Subscribe(userId int64) (<-chan *entity.Event, func()) {
+	s := &subscription{userId: userId, events: make(chan *entity.Event, subscriptionBuffer)}
+
+	b.m.Lock()
+	b.subscriptions[s] = struct{}{}
+	b.m.Unlock()
+
+	return s.events, func() {
+		b.m.Lock()
+		defer b.m.Unlock()
+		if _, ok := b.subscriptions[s]; ok {
+			delete(b.subscriptions, s)
+			close(s.events)
+		}
+	}
+}
+
+func (b *MemoryBus) Close() {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	for s := range b.subscriptions {
+		delete(b.subscriptions, s)
+		close(s.events)
+	}
+}
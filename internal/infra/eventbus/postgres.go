@@ -0,0 +1,76 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/pkg/slogext"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"log/slog"
+)
+
+const pgChannel = "wordka_events"
+
+// PostgresBus spreads events across app instances with LISTEN/NOTIFY: an event published on any
+// instance is delivered to the subscribers of every instance.
+type PostgresBus struct {
+	*MemoryBus
+	db       *bun.DB
+	listener *pgdriver.Listener
+	logger   *slog.Logger
+}
+
+func NewPostgresBus(db *bun.DB, logger *slog.Logger) *PostgresBus {
+	return &PostgresBus{
+		MemoryBus: NewMemoryBus(logger),
+		db:        db,
+		listener:  pgdriver.NewListener(db),
+		logger:    logger,
+	}
+}
+
+// Start listens to the notifications of all instances until the context is cancelled.
+func (b *PostgresBus) Start(ctx context.Context) error {
+	if err := b.listener.Listen(ctx, pgChannel); err != nil {
+		return err
+	}
+
+	go func() {
+		defer func() { _ = b.listener.Close() }()
+
+		notifications := b.listener.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case notification := <-notifications:
+				event := &entity.Event{}
+				if err := json.Unmarshal([]byte(notification.Payload), event); err != nil {
+					slogext.Error(b.logger, err)
+					continue
+				}
+				b.MemoryBus.Publish(event)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// PublishLocal skips the other instances, see Bus.PublishLocal.
+func (b *PostgresBus) PublishLocal(event *entity.Event) {
+	b.MemoryBus.Publish(event)
+}
+
+func (b *PostgresBus) Publish(event *entity.Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		slogext.Error(b.logger, err)
+		return
+	}
+
+	if err := pgdriver.Notify(context.Background(), b.db, pgChannel, string(payload)); err != nil {
+		slogext.Error(b.logger, err)
+	}
+}
@@ -0,0 +1,27 @@
+package mailer
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer saves every email as an .eml file to the directory instead of delivering it.
+type FileMailer struct {
+	from string
+	dir  string
+}
+
+func NewFileMailer(from, dir string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileMailer{from: from, dir: dir}, nil
+}
+
+func (m *FileMailer) Send(message *Message) error {
+	name := time.Now().Format("20060102-150405.000000000") + ".eml"
+
+	return os.WriteFile(filepath.Join(m.dir, name), compose(m.from, message), 0o644)
+}
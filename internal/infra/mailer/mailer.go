@@ -0,0 +1,61 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/mail"
+	"time"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails sent by use cases.
+type Mailer interface {
+	Send(message *Message) error
+}
+
+// LogMailer only logs the emails, so that the links in them can be followed during development.
+type LogMailer struct {
+	logger *slog.Logger
+}
+
+func NewLogMailer(logger *slog.Logger) *LogMailer {
+	return &LogMailer{logger: logger}
+}
+
+func (m *LogMailer) Send(message *Message) error {
+	m.logger.Info("Mailer:Send", "to", message.To, "subject", message.Subject, "body", message.Body)
+	return nil
+}
+
+// compose renders the message in the RFC 5322 format.
+func compose(from string, message *Message) []byte {
+	var b bytes.Buffer
+	_, _ = fmt.Fprintf(&b, "From: %s\r\n", encodeAddress(from))
+	_, _ = fmt.Fprintf(&b, "To: %s\r\n", encodeAddress(message.To))
+	_, _ = fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	_, _ = fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(message.Body)
+
+	return b.Bytes()
+}
+
+// encodeAddress makes an address with a non-ASCII display name, e.g. "Вордка <noreply@wordka.ru>", safe for a header.
+func encodeAddress(address string) string {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return address
+	}
+	return parsed.String()
+}
@@ -0,0 +1,43 @@
+package mailer
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+)
+
+// SmtpMailer delivers emails through an SMTP server, authenticating when a user is configured.
+type SmtpMailer struct {
+	from    string
+	address string
+	auth    smtp.Auth
+}
+
+func NewSmtpMailer(from, host string, port int, user, pass string) *SmtpMailer {
+	var auth smtp.Auth
+	if user != "" {
+		auth = smtp.PlainAuth("", user, pass, host)
+	}
+
+	return &SmtpMailer{from: from, address: net.JoinHostPort(host, strconv.Itoa(port)), auth: auth}
+}
+
+func (m *SmtpMailer) Send(message *Message) error {
+	from, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return fmt.Errorf("Mailer:Smtp | From: %w", err)
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("Mailer:Smtp | To: %w", err)
+	}
+
+	err = smtp.SendMail(m.address, m.auth, from.Address, []string{to.Address}, compose(m.from, message))
+	if err != nil {
+		return fmt.Errorf("Mailer:Smtp | Send: %w", err)
+	}
+
+	return nil
+}
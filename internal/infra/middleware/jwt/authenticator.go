@@ -20,6 +20,10 @@ type UserProvider interface {
 	FindById(id int64) (*entity.User, error)
 }
 
+type RevocationChecker interface {
+	IsTokenRevoked(jti string) (bool, error)
+}
+
 type contextKey struct {
 	name string
 }
@@ -42,7 +46,15 @@ var ErrNoTokenFound = errors.New("no token found")
 // or cookie header is then decoded by the `jwt-go` library and a *jwt.Token
 // object is set on the request context. In the case of a signature decoding error
 // the Authenticator will also set the error on the request context.
-func Authenticator(tv TokenVerifier, up UserProvider, logger *slog.Logger) func(http.Handler) http.Handler {
+//
+// Tokens revoked by their jti, e.g. on logout, are rejected. The legacy tokens without a jti are rejected
+// once all the sessions of the user are revoked after they were issued.
+func Authenticator(
+	tv TokenVerifier,
+	up UserProvider,
+	rc RevocationChecker,
+	logger *slog.Logger,
+) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		hfn := func(w http.ResponseWriter, r *http.Request) {
 			ctx := r.Context()
@@ -61,6 +73,20 @@ func Authenticator(tv TokenVerifier, up UserProvider, logger *slog.Logger) func(
 				return
 			}
 
+			if token.Jti != "" {
+				isRevoked, errRevoked := rc.IsTokenRevoked(token.Jti)
+				if errRevoked != nil {
+					slogext.Error(logger, errRevoked)
+					response.ErrInternalServer(w)
+					return
+				}
+				if isRevoked {
+					logger.Warn("Authentication: Token revoked", "jti", token.Jti)
+					response.ErrHttpError(w, http.StatusUnauthorized, errMsg)
+					return
+				}
+			}
+
 			user, errUserFindById := up.FindById(token.Sub)
 			if errUserFindById != nil {
 				logger.Warn("Authentication: User not found during authorization", "err", errUserFindById)
@@ -68,6 +94,18 @@ func Authenticator(tv TokenVerifier, up UserProvider, logger *slog.Logger) func(
 				return
 			}
 
+			if token.Jti == "" && user.IsIssuedBeforeRevocation(token.Iat) {
+				logger.Warn("Authentication: Token revoked with the user's sessions", "userId", user.Id)
+				response.ErrHttpError(w, http.StatusUnauthorized, errMsg)
+				return
+			}
+
+			if user.IsBanned() {
+				logger.Warn("Authentication: User banned", "userId", user.Id)
+				response.ErrHttpError(w, http.StatusForbidden, "The account is banned.")
+				return
+			}
+
 			ctx = newTokenContext(ctx, token, user, err)
 
 			next.ServeHTTP(w, r.WithContext(ctx))
@@ -0,0 +1,23 @@
+package jwt
+
+import (
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/pkg/http/response"
+	"net/http"
+)
+
+// RequireRole http middleware handler lets only users granted the role through, see entity.User.HasRole.
+// It must be used after the Authenticator, which puts the current user on the request context.
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, _ := r.Context().Value(CurrentUserCtxKey).(*entity.User)
+			if user == nil || !user.HasRole(role) {
+				response.ErrHttpError(w, http.StatusForbidden, "You are not allowed to access this resource.")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package jwt
+
+import (
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/pkg/http/response"
+	"net/http"
+)
+
+// VerifiedEmail http middleware handler lets only users with a verified email through.
+// It must be used after the Authenticator, which puts the current user on the request context.
+func VerifiedEmail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _ := r.Context().Value(CurrentUserCtxKey).(*entity.User)
+		if user == nil || !user.IsEmailVerified() {
+			response.ErrHttpError(
+				w,
+				http.StatusForbidden,
+				"Please verify your email to play. Follow the link from the email we have sent you.",
+			)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
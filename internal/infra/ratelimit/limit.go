@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Limit is a token bucket: a client may make Burst requests at once, after which the bucket
+// refills at Requests per Period. A Limit without Requests does not limit anything.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+func (l Limit) IsDisabled() bool {
+	return l.Requests <= 0 || l.Period <= 0
+}
+
+func (l Limit) capacity() float64 {
+	if l.Burst <= 0 {
+		return float64(l.Requests)
+	}
+
+	return float64(l.Burst)
+}
+
+// rate is how many tokens are added per second.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+func newBucket(limit Limit, now time.Time) *bucket {
+	return &bucket{tokens: limit.capacity(), updatedAt: now}
+}
+
+// take refills the bucket for the time passed since it was last used and takes a token out of it.
+// When the bucket is empty, it returns how long it takes for the next token to come.
+func (b *bucket) take(limit Limit, now time.Time) (bool, time.Duration) {
+	elapsed := max(now.Sub(b.updatedAt).Seconds(), 0)
+	b.tokens = math.Min(limit.capacity(), b.tokens+elapsed*limit.rate())
+	b.updatedAt = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	return false, time.Duration((1 - b.tokens) / limit.rate() * float64(time.Second))
+}
+
+// fullAt is when the bucket is full again, after which it is no different from a new one and can be dropped.
+func (b *bucket) fullAt(limit Limit) time.Time {
+	return b.updatedAt.Add(time.Duration((limit.capacity() - b.tokens) / limit.rate() * float64(time.Second)))
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/Markard/wordka/pkg/http/response"
+	"github.com/Markard/wordka/pkg/slogext"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxBodySize is how much of the body is read looking for the email, larger bodies are not limited by it.
+const maxBodySize = 64 << 10
+
+const tooManyRequestsMsg = "Too many requests, try again later."
+
+// Limiter builds the http middlewares limiting the requests with the buckets of the store.
+// The requests are let through when the store fails: the limits must not take the API down.
+type Limiter struct {
+	store  Store
+	logger *slog.Logger
+}
+
+func NewLimiter(store Store, logger *slog.Logger) *Limiter {
+	return &Limiter{store: store, logger: logger}
+}
+
+// ByIp limits the requests of every client IP. Behind a reverse proxy, the client IP has to be put
+// into the remote address first, e.g. with middleware.RealIP.
+func (l *Limiter) ByIp(name string, limit Limit) func(http.Handler) http.Handler {
+	return l.middleware(name, limit, func(r *http.Request) string {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			return r.RemoteAddr
+		}
+		return host
+	})
+}
+
+// ByEmail limits the requests for every email found in the "email" field of a JSON body,
+// whichever clients they come from. Requests without an email are not limited.
+func (l *Limiter) ByEmail(name string, limit Limit) func(http.Handler) http.Handler {
+	return l.middleware(name, limit, func(r *http.Request) string {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+		if err != nil {
+			return ""
+		}
+
+		var req struct {
+			Email string `json:"email"`
+		}
+		if err = json.Unmarshal(body, &req); err != nil {
+			return ""
+		}
+		return strings.ToLower(strings.TrimSpace(req.Email))
+	})
+}
+
+func (l *Limiter) middleware(name string, limit Limit, keyOf func(r *http.Request) string) func(http.Handler) http.Handler {
+	if limit.IsDisabled() {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := keyOf(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed, retryAfter, err := l.store.Take(name+":"+key, limit)
+			if err != nil {
+				slogext.Error(l.logger, err)
+			} else if !allowed {
+				l.logger.Warn("RateLimit: Request throttled", "limit", name, "key", key, "path", r.URL.Path)
+				response.ErrTooManyRequests(w, max(retryAfter, time.Second), tooManyRequestsMsg)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/uptrace/bun"
+	"sync"
+	"time"
+)
+
+type bucketRow struct {
+	bun.BaseModel `bun:"table:rate_limit_buckets"`
+
+	Key       string    `bun:"key,pk"`
+	Tokens    float64   `bun:"tokens,notnull"`
+	UpdatedAt time.Time `bun:"updated_at,notnull"`
+	ExpiresAt time.Time `bun:"expires_at,notnull"`
+}
+
+// PostgresStore keeps the buckets in the database, so the limits hold across app instances.
+type PostgresStore struct {
+	db        *bun.DB
+	m         sync.Mutex
+	nextSweep time.Time
+}
+
+func NewPostgresStore(db *bun.DB) *PostgresStore {
+	return &PostgresStore{db: db, nextSweep: time.Now().Add(sweepInterval)}
+}
+
+// Take refills the bucket and takes a token out of it in a single upsert, so concurrent requests take tokens
+// one by one, the first ones for a key included.
+//
+// The statement always takes a token, a bucket left below zero marks the request as denied: the denied request
+// took nothing, so such a bucket holds one token more than stored.
+func (s *PostgresStore) Take(key string, limit Limit) (bool, time.Duration, error) {
+	ctx := context.Background()
+	if err := s.sweep(ctx); err != nil {
+		return false, 0, err
+	}
+
+	now := time.Now()
+	capacity := limit.capacity()
+	row := &bucketRow{
+		Key:       key,
+		Tokens:    capacity - 1,
+		UpdatedAt: now,
+		// A bucket gets full at most after the time it takes to refill from empty.
+		ExpiresAt: now.Add(time.Duration(capacity / limit.rate() * float64(time.Second))),
+	}
+
+	var tokens float64
+	err := s.db.NewInsert().
+		Model(row).
+		On("CONFLICT (key) DO UPDATE").
+		Set(`tokens = LEAST(?,
+			CASE WHEN ?TableAlias.tokens < 0 THEN ?TableAlias.tokens + 1 ELSE ?TableAlias.tokens END
+			+ GREATEST(EXTRACT(EPOCH FROM EXCLUDED.updated_at - ?TableAlias.updated_at), 0) * ?
+		) - 1`, capacity, limit.rate()).
+		Set("updated_at = GREATEST(?TableAlias.updated_at, EXCLUDED.updated_at)").
+		Set("expires_at = EXCLUDED.expires_at").
+		Returning("tokens").
+		Scan(ctx, &tokens)
+	if err != nil {
+		return false, 0, err
+	}
+
+	if tokens >= 0 {
+		return true, 0, nil
+	}
+
+	return false, time.Duration(-tokens / limit.rate() * float64(time.Second)), nil
+}
+
+// sweep deletes the buckets which have filled up again, at most once per sweepInterval.
+func (s *PostgresStore) sweep(ctx context.Context) error {
+	s.m.Lock()
+	now := time.Now()
+	if now.Before(s.nextSweep) {
+		s.m.Unlock()
+		return nil
+	}
+	s.nextSweep = now.Add(sweepInterval)
+	s.m.Unlock()
+
+	_, err := s.db.NewDelete().Model((*bucketRow)(nil)).Where("expires_at < ?", now).Exec(ctx)
+	return err
+}
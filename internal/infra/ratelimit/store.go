@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the stores drop the buckets which have filled up again.
+const sweepInterval = time.Minute
+
+// Store keeps the token buckets of the clients.
+type Store interface {
+	// Take takes a token out of the bucket under the key. When the bucket is empty,
+	// it returns false and how long it takes for the next token to come.
+	Take(key string, limit Limit) (bool, time.Duration, error)
+}
+
+// MemoryStore keeps the buckets in the memory of the current app instance, so every instance limits on its own.
+type MemoryStore struct {
+	m         sync.Mutex
+	buckets   map[string]*bucket
+	limits    map[string]Limit
+	nextSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*bucket),
+		limits:    make(map[string]Limit),
+		nextSweep: time.Now().Add(sweepInterval),
+	}
+}
+
+func (s *MemoryStore) Take(key string, limit Limit) (bool, time.Duration, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	now := time.Now()
+	if now.After(s.nextSweep) {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = newBucket(limit, now)
+		s.buckets[key] = b
+		s.limits[key] = limit
+	}
+	allowed, retryAfter := b.take(limit, now)
+
+	return allowed, retryAfter, nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if b.fullAt(s.limits[key]).Before(now) {
+			delete(s.buckets, key)
+			delete(s.limits, key)
+		}
+	}
+	s.nextSweep = now.Add(sweepInterval)
+}
@@ -0,0 +1,74 @@
+package jwt
+
+import (
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"strconv"
+	"time"
+)
+
+const actionClaim = "act"
+
+const (
+	ActionVerifyEmail   = "verify_email"
+	ActionResetPassword = "reset_password"
+)
+
+var ErrWrongAction = errors.New("token was issued for another action")
+
+// ActionToken authorizes a single action of the user outside of a session, e.g. following a link from an email.
+// It is bound to the email of the user, so that it stops working once the email changes,
+// and optionally to a stamp of other user data, e.g. of the password to make a reset token single-use.
+type ActionToken struct {
+	Action string
+	Sub    int64
+	Email  string
+	Stamp  string
+	Exp    time.Time
+}
+
+func NewActionToken(action string, sub int64, email string, stamp string, exp time.Time) *ActionToken {
+	return &ActionToken{Action: action, Sub: sub, Email: email, Stamp: stamp, Exp: exp}
+}
+
+func (s Service) CreateActionTokenStringWithES256(t *ActionToken) (string, error) {
+	return s.sign(jwt.MapClaims{
+		actionClaim: t.Action,
+		"sub":       strconv.FormatInt(t.Sub, 10),
+		"email":     t.Email,
+		"stamp":     t.Stamp,
+		"exp":       t.Exp.Unix(),
+		"iat":       time.Now().Unix(),
+	})
+}
+
+// VerifyActionTokenStringWithES256 accepts only a token issued for the action.
+func (s Service) VerifyActionTokenStringWithES256(tokenString string, action string) (*ActionToken, error) {
+	claims, err := s.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claimedAction, _ := claims[actionClaim].(string); claimedAction != action {
+		return nil, ErrWrongAction
+	}
+
+	sub, err := subject(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	email, ok := claims["email"].(string)
+	if !ok {
+		return nil, errors.New("token has no email claim")
+	}
+
+	stamp, _ := claims["stamp"].(string)
+
+	exp, err := claims.GetExpirationTime()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewActionToken(action, sub, email, stamp, exp.Time), nil
+}
@@ -0,0 +1,53 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"encoding/base64"
+)
+
+// Jwk is the public part of a key in the JSON Web Key format, RFC 7517.
+type Jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+}
+
+func NewJwk(id string, publicKey *ecdsa.PublicKey) *Jwk {
+	// The coordinates of a P-256 point are 32 bytes long, left-padded with zeros.
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	publicKey.X.FillBytes(x)
+	publicKey.Y.FillBytes(y)
+
+	jwk := &Jwk{
+		Kty: "EC",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(x),
+		Y:   base64.RawURLEncoding.EncodeToString(y),
+	}
+	if id != "" {
+		jwk.Kid = id
+		jwk.Use = "sig"
+		jwk.Alg = "ES256"
+	}
+
+	return jwk
+}
+
+// Jwks is the JSON Web Key Set other services verify the tokens with.
+type Jwks struct {
+	Keys []*Jwk `json:"keys"`
+}
+
+func (ks *KeySet) Jwks() *Jwks {
+	jwks := &Jwks{Keys: make([]*Jwk, 0, len(ks.verifiers))}
+	for _, key := range ks.Verifiers() {
+		jwks.Keys = append(jwks.Keys, NewJwk(key.Id, key.PublicKey))
+	}
+
+	return jwks
+}
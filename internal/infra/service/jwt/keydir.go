@@ -0,0 +1,231 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// A key directory keeps every key as "<kid>.pub" and the private part of the active one as "<kid>.key",
+// the file "active" holds the id of the active key and "<kid>.retired" the time a key was retired.
+const (
+	activeKeyFile     = "active"
+	privateKeyFileExt = ".key"
+	publicKeyFileExt  = ".pub"
+	retiredFileExt    = ".retired"
+	// keyIdLayout names the keys after the time they are generated, so their ids sort by age.
+	keyIdLayout = "20060102T150405Z"
+)
+
+var ErrNoActiveKey = errors.New("the key directory has no active key")
+
+// LoadKeyDir parses all the keys of the directory, ErrNoActiveKey is returned until keys are rotated into it.
+func LoadKeyDir(dir string) (*KeySet, error) {
+	activeId, err := os.ReadFile(filepath.Join(dir, activeKeyFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNoActiveKey
+		}
+		return nil, err
+	}
+
+	signer, err := readKey(dir, strings.TrimSpace(string(activeId)), true)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+publicKeyFileExt))
+	if err != nil {
+		return nil, err
+	}
+	verifiers := make([]*Key, 0, len(paths))
+	for _, path := range paths {
+		id := strings.TrimSuffix(filepath.Base(path), publicKeyFileExt)
+		if id == signer.Id {
+			continue
+		}
+		key, err := readKey(dir, id, false)
+		if err != nil {
+			return nil, err
+		}
+		verifiers = append(verifiers, key)
+	}
+
+	keys, err := NewKeySet(signer, verifiers...)
+	if err != nil {
+		return nil, err
+	}
+	// The key of the environment is kept under its thumbprint, while the generated keys are named by time.
+	for _, key := range verifiers {
+		if key.Id == Thumbprint(key.PublicKey) {
+			keys.legacy = key
+		}
+	}
+
+	return keys, nil
+}
+
+// RotateKeys generates a new active key in the directory. The private part of the previous one is deleted,
+// while its public part is kept to verify the tokens it has signed for the retain period. The keys of the
+// current set missing from the directory, e.g. given through the environment, are kept the same way.
+// The app loads the keys at startup, so it has to be restarted to sign with the new key.
+func RotateKeys(dir string, current *KeySet, retain time.Duration) (*Key, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if current != nil {
+		for _, key := range current.Verifiers() {
+			path := filepath.Join(dir, key.Id+publicKeyFileExt)
+			if _, err := os.Stat(path); err == nil {
+				continue
+			}
+			if err := writeKeyFile(path, key.PublicKey, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	key := &Key{Id: now.UTC().Format(keyIdLayout), PrivateKey: privateKey, PublicKey: &privateKey.PublicKey}
+	if current != nil && current.Signer().Id == key.Id {
+		return nil, fmt.Errorf("the key %s already exists, rotate again in a second", key.Id)
+	}
+	if err = writeKeyFile(filepath.Join(dir, key.Id+publicKeyFileExt), key.PublicKey, nil); err != nil {
+		return nil, err
+	}
+	if err = writeKeyFile(filepath.Join(dir, key.Id+privateKeyFileExt), nil, key.PrivateKey); err != nil {
+		return nil, err
+	}
+	if err = writeFileAtomically(filepath.Join(dir, activeKeyFile), []byte(key.Id+"\n"), 0600); err != nil {
+		return nil, err
+	}
+
+	return key, retireKeys(dir, key.Id, now, retain)
+}
+
+// retireKeys deletes the private parts of the inactive keys and records the time they were retired,
+// then deletes the keys retired longer than the retain period ago.
+func retireKeys(dir string, activeId string, now time.Time, retain time.Duration) error {
+	privatePaths, err := filepath.Glob(filepath.Join(dir, "*"+privateKeyFileExt))
+	if err != nil {
+		return err
+	}
+	for _, path := range privatePaths {
+		if strings.TrimSuffix(filepath.Base(path), privateKeyFileExt) == activeId {
+			continue
+		}
+		if err = os.Remove(path); err != nil {
+			return err
+		}
+	}
+
+	publicPaths, err := filepath.Glob(filepath.Join(dir, "*"+publicKeyFileExt))
+	if err != nil {
+		return err
+	}
+	for _, path := range publicPaths {
+		id := strings.TrimSuffix(filepath.Base(path), publicKeyFileExt)
+		if id == activeId {
+			continue
+		}
+		retiredPath := filepath.Join(dir, id+retiredFileExt)
+		retiredAt, err := readRetiredAt(retiredPath)
+		if errors.Is(err, os.ErrNotExist) {
+			retiredAt = now
+			err = writeFileAtomically(retiredPath, []byte(now.UTC().Format(time.RFC3339)+"\n"), 0644)
+		}
+		if err != nil {
+			return err
+		}
+		if now.Sub(retiredAt) > retain {
+			if err = os.Remove(path); err != nil {
+				return err
+			}
+			if err = os.Remove(retiredPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func readRetiredAt(path string) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	retiredAt, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+
+	return retiredAt, nil
+}
+
+func readKey(dir string, id string, isPrivate bool) (*Key, error) {
+	if id == "" {
+		return nil, ErrNoActiveKey
+	}
+
+	if isPrivate {
+		data, err := os.ReadFile(filepath.Join(dir, id+privateKeyFileExt))
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := parsePrivateKeyPem(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", id, err)
+		}
+		return &Key{Id: id, PrivateKey: privateKey, PublicKey: &privateKey.PublicKey}, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, id+publicKeyFileExt))
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := parsePublicKeyPem(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", id, err)
+	}
+
+	return &Key{Id: id, PublicKey: publicKey}, nil
+}
+
+// writeKeyFile saves either part of a key as PEM, the private one readable by the owner only.
+func writeKeyFile(path string, publicKey *ecdsa.PublicKey, privateKey *ecdsa.PrivateKey) error {
+	var data []byte
+	var err error
+	perm := os.FileMode(0644)
+	if privateKey != nil {
+		data, err = encodePrivateKeyPem(privateKey)
+		perm = 0600
+	} else {
+		data, err = encodePublicKeyPem(publicKey)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomically(path, data, perm)
+}
+
+func writeFileAtomically(path string, data []byte, perm os.FileMode) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,159 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Key is an ES256 key identified by the "kid" header of the tokens it signs.
+// Only the active key of a KeySet has the private part, the others just verify.
+type Key struct {
+	Id         string
+	PrivateKey *ecdsa.PrivateKey
+	PublicKey  *ecdsa.PublicKey
+}
+
+// KeySet holds the parsed keys: the active one signs new tokens, all of them verify tokens,
+// so the tokens signed before a rotation keep working until they expire.
+type KeySet struct {
+	signer    *Key
+	verifiers map[string]*Key
+	// legacy is the key of the environment, which signed the tokens before the keys had ids.
+	legacy *Key
+}
+
+func NewKeySet(signer *Key, verifiers ...*Key) (*KeySet, error) {
+	if signer == nil || signer.PrivateKey == nil {
+		return nil, errors.New("the active key has no private key")
+	}
+
+	ks := &KeySet{signer: signer, verifiers: map[string]*Key{signer.Id: signer}}
+	for _, key := range verifiers {
+		ks.verifiers[key.Id] = key
+	}
+
+	return ks, nil
+}
+
+// NewKeySetFromPem makes a key set of a single pair, e.g. given through the environment.
+// The key id is derived from the public key, see Thumbprint.
+func NewKeySetFromPem(privateKeyPem, publicKeyPem string) (*KeySet, error) {
+	privateKey, err := parsePrivateKeyPem([]byte(privateKeyPem))
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := parsePublicKeyPem([]byte(publicKeyPem))
+	if err != nil {
+		return nil, err
+	}
+	if !privateKey.PublicKey.Equal(publicKey) {
+		return nil, errors.New("the public key does not match the private key")
+	}
+
+	keys, err := NewKeySet(&Key{Id: Thumbprint(publicKey), PrivateKey: privateKey, PublicKey: publicKey})
+	if err != nil {
+		return nil, err
+	}
+	keys.legacy = keys.signer
+
+	return keys, nil
+}
+
+// Signer returns the active key.
+func (ks *KeySet) Signer() *Key {
+	return ks.signer
+}
+
+// Verifier returns the key with the id. Tokens without a key id were signed with the key of the environment
+// before the keys had ids, they are checked with it while it is retained after the keys are rotated.
+func (ks *KeySet) Verifier(id string) (*Key, bool) {
+	if id == "" {
+		return ks.legacy, ks.legacy != nil
+	}
+	key, ok := ks.verifiers[id]
+
+	return key, ok
+}
+
+// Verifiers returns all the keys ordered by id.
+func (ks *KeySet) Verifiers() []*Key {
+	keys := make([]*Key, 0, len(ks.verifiers))
+	for _, key := range ks.verifiers {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b *Key) int { return strings.Compare(a.Id, b.Id) })
+
+	return keys
+}
+
+// Thumbprint is the RFC 7638 thumbprint of the public key, shortened to serve as a key id.
+func Thumbprint(publicKey *ecdsa.PublicKey) string {
+	jwk := NewJwk("", publicKey)
+	// The members are required in the lexicographic order, with no whitespace.
+	canonical := fmt.Sprintf(`{"crv":"%s","kty":"%s","x":"%s","y":"%s"}`, jwk.Crv, jwk.Kty, jwk.X, jwk.Y)
+	sum := sha256.Sum256([]byte(canonical))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func parsePrivateKeyPem(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "EC PRIVATE KEY" {
+		return nil, errors.New("invalid PEM block for EC PRIVATE KEY")
+	}
+
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if key.Curve != elliptic.P256() {
+		return nil, errors.New("ES256 requires a P-256 key")
+	}
+
+	return key, nil
+}
+
+func parsePublicKeyPem(data []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "EC PUBLIC KEY" {
+		return nil, errors.New("invalid PEM block for EC PUBLIC KEY")
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	ecdsaPub, ok := pub.(*ecdsa.PublicKey)
+	if !ok || ecdsaPub.Curve != elliptic.P256() {
+		return nil, errors.New("invalid ECDSA P-256 public key")
+	}
+
+	return ecdsaPub, nil
+}
+
+func encodePrivateKeyPem(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+func encodePublicKeyPem(key *ecdsa.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PUBLIC KEY", Bytes: der}), nil
+}
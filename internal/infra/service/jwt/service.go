@@ -1,9 +1,6 @@
 package jwt
 
 import (
-	"crypto/ecdsa"
-	"crypto/x509"
-	"encoding/pem"
 	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
@@ -11,121 +8,224 @@ import (
 	"time"
 )
 
+// Token is an access token. Its roles are for the services verifying the tokens with the JWKS,
+// the app itself loads the user on every request.
 type Token struct {
-	Sub int64
-	Exp time.Time
-	Iat time.Time
+	Jti   string
+	Sub   int64
+	Sid   string
+	Roles []string
+	Iat   time.Time
+	Nbf   time.Time
+	Exp   time.Time
 }
 
-func NewToken(sub int64, iat time.Time, exp time.Time) *Token {
-	return &Token{Sub: sub, Iat: iat, Exp: exp}
+func NewToken(jti string, sub int64, sid string, roles []string, iat time.Time, exp time.Time) *Token {
+	return &Token{Jti: jti, Sub: sub, Sid: sid, Roles: roles, Iat: iat, Nbf: iat, Exp: exp}
 }
 
+// Options bind the tokens to the environment issuing them: a token is accepted only with the issuer
+// and audience of the current one, so dev tokens are of no use in prod. Leeway tolerates clock skew
+// between the servers when checking the times of a token.
+type Options struct {
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+}
+
+// legacyTokenMaxTtl is the longest lifetime of the tokens issued before they carried the issuer, audience, session
+// and not-before claims. Such tokens are accepted for the lifetime they were issued with, so the sessions and
+// the emailed links of the time before stay valid. No more of them are issued, so the last one expires
+// at most this long after the deploy.
+const legacyTokenMaxTtl = 7 * 24 * time.Hour
+
+// Service signs and verifies the tokens with the keys parsed once, at startup.
 type Service struct {
-	privateKeyStr string
-	publicKeyStr  string
+	keys    *KeySet
+	options Options
 }
 
-func NewService(privateKeyStr, publicKeyStr string) *Service {
-	return &Service{
-		privateKeyStr: privateKeyStr,
-		publicKeyStr:  publicKeyStr,
-	}
+func NewService(keys *KeySet, options Options) *Service {
+	return &Service{keys: keys, options: options}
+}
+
+// Jwks returns the public keys the tokens are verified with.
+func (s Service) Jwks() *Jwks {
+	return s.keys.Jwks()
+}
+
+func (s Service) CreateTokenStringWithES256(t *Token) (string, error) {
+	return s.sign(jwt.MapClaims{
+		"jti":   t.Jti,                        // token id, the key of the revocation list
+		"sub":   strconv.FormatInt(t.Sub, 10), // user id
+		"sid":   t.Sid,                        // session id
+		"roles": t.Roles,                      // roles of the user when the token was issued
+		"exp":   t.Exp.Unix(),                 // expiration date
+		"nbf":   t.Nbf.Unix(),                 // date the token is valid from
+		"iat":   t.Iat.Unix(),                 // creation date
+	})
 }
 
-func (s Service) CreateTokenStringWithES256(userId int64) (string, error) {
-	privateKey, err := s.parseECDSAPrivateKeyStr()
+func (s Service) VerifyTokenStringWithES256(tokenString string) (*Token, error) {
+	claims, err := s.parse(tokenString)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-
-	claims := jwt.MapClaims{
-		"sub": strconv.FormatInt(userId, 10),
-		"exp": time.Now().Add(time.Hour * 24 * 7).Unix(), // expiration date
-		"iat": time.Now().Unix(),                         // creation date
+	if _, isAction := claims[actionClaim]; isAction {
+		return nil, errors.New("action token used as an access token")
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
-
-	tokenString, err := token.SignedString(privateKey)
+	sub, err := subject(claims)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return tokenString, nil
-}
-
-func (s Service) VerifyTokenStringWithES256(tokenString string) (*Token, error) {
-	publicKey, err := s.parseECDSAPublicKeyFromPEM()
+	exp, err := claims.GetExpirationTime()
 	if err != nil {
 		return nil, err
 	}
 
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok || token.Method.Alg() != jwt.SigningMethodES256.Alg() {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return publicKey, nil
-	})
+	iat, err := claims.GetIssuedAt()
+	if err != nil || iat == nil {
+		return nil, errors.New("token has no iat claim")
+	}
 
-	if err != nil {
-		return nil, err
+	// A legacy token has no session, the session is found by the jti then. The tokens issued before
+	// they had a jti are revoked only with all the sessions of the user.
+	jti, _ := claims["jti"].(string)
+	if isLegacy(claims) {
+		return NewToken(jti, sub, "", nil, iat.Time, exp.Time), nil
 	}
-	if !token.Valid {
-		return nil, errors.New("invalid token")
+	if jti == "" {
+		return nil, errors.New("token has no jti claim")
 	}
 
-	subStr, err := token.Claims.GetSubject()
-	if err != nil {
-		return nil, err
+	sid, ok := claims["sid"].(string)
+	if !ok || sid == "" {
+		return nil, errors.New("token has no sid claim")
 	}
-	sub, err := strconv.ParseInt(subStr, 10, 64)
+
+	roles, err := stringList(claims, "roles")
 	if err != nil {
 		return nil, err
 	}
 
-	exp, err := token.Claims.GetExpirationTime()
-	if err != nil {
-		return nil, err
+	nbf, err := claims.GetNotBefore()
+	if err != nil || nbf == nil {
+		return nil, errors.New("token has no nbf claim")
 	}
 
-	iat, err := token.Claims.GetIssuedAt()
+	token := NewToken(jti, sub, sid, roles, iat.Time, exp.Time)
+	token.Nbf = nbf.Time
+
+	return token, nil
+}
+
+func (s Service) sign(claims jwt.MapClaims) (string, error) {
+	signer := s.keys.Signer()
+	claims["iss"] = s.options.Issuer
+	claims["aud"] = s.options.Audience
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = signer.Id
+
+	tokenString, err := token.SignedString(signer.PrivateKey)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return NewToken(sub, iat.Time, exp.Time), nil
+	return tokenString, nil
 }
 
-func (s Service) parseECDSAPrivateKeyStr() (*ecdsa.PrivateKey, error) {
-	block, _ := pem.Decode([]byte(s.privateKeyStr))
-	if block == nil || block.Type != "EC PRIVATE KEY" {
-		return nil, errors.New("invalid PEM block for EC PRIVATE KEY")
+func (s Service) parse(tokenString string) (jwt.MapClaims, error) {
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok || token.Method.Alg() != jwt.SigningMethodES256.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		key, ok := s.keys.Verifier(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key: %s", kid)
+		}
+		return key.PublicKey, nil
+	}
+
+	token, err := jwt.Parse(
+		tokenString,
+		keyFunc,
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithIssuer(s.options.Issuer),
+		jwt.WithAudience(s.options.Audience),
+		jwt.WithLeeway(s.options.Leeway),
+	)
+	if err != nil {
+		legacyToken, errLegacy := jwt.Parse(
+			tokenString,
+			keyFunc,
+			jwt.WithExpirationRequired(),
+			jwt.WithIssuedAt(),
+			jwt.WithLeeway(s.options.Leeway),
+		)
+		if errLegacy == nil && isLegacy(legacyToken.Claims.(jwt.MapClaims)) {
+			token, err = legacyToken, nil
+		}
 	}
-
-	key, err := x509.ParseECPrivateKey(block.Bytes)
 	if err != nil {
 		return nil, err
 	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("invalid token claims")
+	}
 
-	return key, nil
+	return claims, nil
 }
 
-func (s Service) parseECDSAPublicKeyFromPEM() (*ecdsa.PublicKey, error) {
-	block, _ := pem.Decode([]byte(s.publicKeyStr))
-	if block == nil || block.Type != "EC PUBLIC KEY" {
-		return nil, errors.New("invalid PEM block for EC PUBLIC KEY")
+// isLegacy tells the tokens issued before they carried the issuer and the audience, within the lifetime
+// such tokens were issued with.
+func isLegacy(claims jwt.MapClaims) bool {
+	_, hasIssuer := claims["iss"]
+	_, hasAudience := claims["aud"]
+	if hasIssuer || hasAudience {
+		return false
+	}
+
+	iat, errIat := claims.GetIssuedAt()
+	exp, errExp := claims.GetExpirationTime()
+	if errIat != nil || errExp != nil || iat == nil || exp == nil {
+		return false
 	}
 
-	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	return exp.Sub(iat.Time) <= legacyTokenMaxTtl
+}
+
+func subject(claims jwt.MapClaims) (int64, error) {
+	subStr, err := claims.GetSubject()
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	ecdsaPub, ok := pub.(*ecdsa.PublicKey)
+	return strconv.ParseInt(subStr, 10, 64)
+}
+
+func stringList(claims jwt.MapClaims, name string) ([]string, error) {
+	values, ok := claims[name].([]interface{})
 	if !ok {
-		return nil, errors.New("invalid ECDSA public key")
+		return nil, fmt.Errorf("token has no %s claim", name)
+	}
+
+	list := make([]string, 0, len(values))
+	for _, v := range values {
+		str, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("token has an invalid %s claim", name)
+		}
+		list = append(list, str)
 	}
 
-	return ecdsaPub, nil
+	return list, nil
 }
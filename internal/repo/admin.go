@@ -0,0 +1,218 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// AdminSource is the provenance of the words added through the admin API.
+const AdminSource = "admin"
+
+var ErrWordUniqConstraint = errors.New("word already exists")
+
+// AdminRepository persists the changes made through the admin API, each together with its audit entry.
+type AdminRepository struct {
+	pgDb *bun.DB
+}
+
+func NewAdminRepository(pgDb *bun.DB) *AdminRepository {
+	return &AdminRepository{pgDb: pgDb}
+}
+
+func (r *AdminRepository) FindUser(id int64) (*entity.User, error) {
+	user := &entity.User{}
+	err := r.pgDb.NewSelect().
+		Model(user).
+		Where("id = ?", id).
+		Where("deleted_at IS NULL").
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UpdateBan saves only the ban of the user.
+func (r *AdminRepository) UpdateBan(user *entity.User, entry *entity.AuditEntry) error {
+	return r.audited(entry, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().Model(user).Column("banned_at", "ban_reason", "updated_at").WherePK().Exec(ctx)
+		return err
+	})
+}
+
+// UpdateRole saves only the role of the user.
+func (r *AdminRepository) UpdateRole(user *entity.User, entry *entity.AuditEntry) error {
+	return r.audited(entry, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().Model(user).Column("role", "updated_at").WherePK().Exec(ctx)
+		return err
+	})
+}
+
+// ResetStats saves the new stats reset date of the user and drops the aggregates of their games,
+// the rebuilds then skip the games finished before the reset.
+func (r *AdminRepository) ResetStats(user *entity.User, entry *entity.AuditEntry) error {
+	return r.audited(entry, func(ctx context.Context, tx bun.Tx) error {
+		_, errUser := tx.NewUpdate().Model(user).Column("stats_reset_at", "updated_at").WherePK().Exec(ctx)
+		if errUser != nil {
+			return errUser
+		}
+
+		models := []any{(*entity.UserStats)(nil), (*entity.LeaderboardEntry)(nil), (*entity.DailyResult)(nil)}
+		for _, model := range models {
+			if _, err := tx.NewDelete().Model(model).Where("user_id = ?", user.Id).Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// FindWord returns the word whatever its tier, disabled words included.
+func (r *AdminRepository) FindWord(word string) (*entity.Word, error) {
+	w := &entity.Word{}
+	err := r.pgDb.NewSelect().
+		Model(w).
+		Where("word = ?", word).
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (r *AdminRepository) AddWord(word *entity.Word, entry *entity.AuditEntry) error {
+	return r.audited(entry, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewInsert().Model(word).Returning("id").Exec(ctx)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return ErrWordUniqConstraint
+			}
+			return err
+		}
+
+		now := time.Now()
+		_, err = tx.NewInsert().
+			Model(&entity.WordSource{WordId: word.Id, Source: AdminSource, FirstSeenAt: now, LastSeenAt: now}).
+			Exec(ctx)
+
+		return err
+	})
+}
+
+func (r *AdminRepository) UpdateWord(word *entity.Word, entry *entity.AuditEntry) error {
+	return r.audited(entry, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().Model(word).Column(wordFlagColumns...).WherePK().Exec(ctx)
+		return err
+	})
+}
+
+// FindDailyPuzzles returns the puzzles scheduled from the date on, in the order of their dates.
+func (r *AdminRepository) FindDailyPuzzles(from time.Time, limit int) ([]*entity.DailyPuzzle, error) {
+	puzzles := make([]*entity.DailyPuzzle, 0, limit)
+	err := r.pgDb.NewSelect().
+		Model(&puzzles).
+		Relation("Word").
+		Where("?TableAlias.date >= ?", from.Format(time.DateOnly)).
+		OrderExpr("?TableAlias.date ASC").
+		Limit(limit).
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return puzzles, nil
+}
+
+func (r *AdminRepository) FindDailyPuzzle(date time.Time) (*entity.DailyPuzzle, error) {
+	puzzle := &entity.DailyPuzzle{}
+	err := r.pgDb.NewSelect().
+		Model(puzzle).
+		Relation("Word").
+		Where("?TableAlias.date = ?", date.Format(time.DateOnly)).
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return puzzle, nil
+}
+
+// IsDailyPuzzlePlayed tells whether somebody has started the puzzle, after which its word must not change.
+func (r *AdminRepository) IsDailyPuzzlePlayed(puzzle *entity.DailyPuzzle) (bool, error) {
+	return r.pgDb.NewSelect().
+		Model((*entity.Game)(nil)).
+		Where("daily_puzzle_id = ?", puzzle.Id).
+		Exists(context.Background())
+}
+
+func (r *AdminRepository) CreateDailyPuzzle(puzzle *entity.DailyPuzzle, entry *entity.AuditEntry) error {
+	return r.audited(entry, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewInsert().Model(puzzle).Returning("id").Exec(ctx)
+		if err != nil && isUniqueViolation(err) {
+			return ErrDailyPuzzleDateUniqConstraint
+		}
+		return err
+	})
+}
+
+// UpdateDailyPuzzles saves the words of the puzzles, provided nobody has started any of them.
+// The check is part of the update, so a puzzle started meanwhile is left as is: all the puzzles are,
+// and sql.ErrNoRows is returned.
+func (r *AdminRepository) UpdateDailyPuzzles(puzzles []*entity.DailyPuzzle, entry *entity.AuditEntry) error {
+	return r.audited(entry, func(ctx context.Context, tx bun.Tx) error {
+		for _, puzzle := range puzzles {
+			err := tx.NewUpdate().
+				Model(puzzle).
+				Column("word_id").
+				WherePK().
+				Where("NOT EXISTS (?)", tx.NewSelect().
+					Model((*entity.Game)(nil)).
+					ColumnExpr("1").
+					Where("daily_puzzle_id = ?", puzzle.Id)).
+				Returning("word_id").
+				Scan(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindAuditEntries returns the latest audit entries, older than the entry with the id when it is not zero.
+func (r *AdminRepository) FindAuditEntries(beforeId int64, limit int) ([]*entity.AuditEntry, error) {
+	entries := make([]*entity.AuditEntry, 0, limit)
+	q := r.pgDb.NewSelect().
+		Model(&entries).
+		Relation("Actor").
+		OrderExpr("?TableAlias.id DESC").
+		Limit(limit)
+	if beforeId > 0 {
+		q = q.Where("?TableAlias.id < ?", beforeId)
+	}
+
+	if err := q.Scan(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *AdminRepository) audited(entry *entity.AuditEntry, change func(ctx context.Context, tx bun.Tx) error) error {
+	ctx := context.Background()
+
+	return r.pgDb.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := change(ctx, tx); err != nil {
+			return err
+		}
+
+		_, err := tx.NewInsert().Model(entry).Returning("id").Exec(ctx)
+		return err
+	})
+}
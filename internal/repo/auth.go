@@ -4,9 +4,8 @@ import (
 	"context"
 	"errors"
 	"github.com/Markard/wordka/internal/entity"
-	"github.com/jackc/pgerrcode"
 	"github.com/uptrace/bun"
-	"github.com/uptrace/bun/driver/pgdriver"
+	"time"
 )
 
 var ErrEmailUniqConstraint = errors.New("email already exists")
@@ -22,8 +21,7 @@ func NewAuthRepository(pgDb *bun.DB) *AuthRepository {
 func (r AuthRepository) Create(user *entity.User) error {
 	_, err := r.pgDb.NewInsert().Model(user).Returning("id").Exec(context.Background())
 	if err != nil {
-		var pgErr pgdriver.Error
-		if errors.As(err, &pgErr) && pgErr.IntegrityViolation() && pgErr.Field('C') == pgerrcode.UniqueViolation {
+		if isUniqueViolation(err) {
 			return ErrEmailUniqConstraint
 		} else {
 			return err
@@ -38,6 +36,7 @@ func (r AuthRepository) FindBy(email string) (*entity.User, error) {
 	err := r.pgDb.NewSelect().
 		Model(user).
 		Where("email = ?", email).
+		Where("deleted_at IS NULL").
 		Scan(context.Background())
 
 	if err != nil {
@@ -52,6 +51,7 @@ func (r AuthRepository) FindById(id int64) (*entity.User, error) {
 	err := r.pgDb.NewSelect().
 		Model(user).
 		Where("id = ?", id).
+		Where("deleted_at IS NULL").
 		Scan(context.Background())
 
 	if err != nil {
@@ -60,3 +60,117 @@ func (r AuthRepository) FindById(id int64) (*entity.User, error) {
 
 	return user, nil
 }
+
+// RecordFailedLogin counts a failed login of the user in a single statement, so no concurrent attempt
+// goes uncounted, and loads the new count into the user.
+func (r AuthRepository) RecordFailedLogin(user *entity.User) error {
+	return r.pgDb.NewUpdate().
+		Model(user).
+		Set("failed_logins = failed_logins + 1").
+		WherePK().
+		Returning("failed_logins").
+		Scan(context.Background())
+}
+
+// LockOut saves the lockout of the user, never shortening a longer one saved by a concurrent failed login.
+func (r AuthRepository) LockOut(user *entity.User) error {
+	_, err := r.pgDb.NewUpdate().
+		Model(user).
+		Set("locked_until = GREATEST(locked_until, ?)", user.LockedUntil.Time).
+		WherePK().
+		Exec(context.Background())
+	return err
+}
+
+// UpdateLoginFailures saves only the failed logins counter and the lockout, leaving the rest of the user as is.
+func (r AuthRepository) UpdateLoginFailures(user *entity.User) error {
+	_, err := r.pgDb.NewUpdate().
+		Model(user).
+		Column("failed_logins", "locked_until").
+		WherePK().
+		Exec(context.Background())
+	return err
+}
+
+// ClaimVerificationResend marks a verification email as sent to the user, unless one was sent within the period.
+// The check and the mark are a single statement, so concurrent requests cannot both pass the throttle:
+// sql.ErrNoRows is returned when the user has to wait.
+func (r AuthRepository) ClaimVerificationResend(user *entity.User, period time.Duration) error {
+	now := time.Now()
+	return r.pgDb.NewUpdate().
+		Model(user).
+		Set("email_verification_sent_at = ?", now).
+		WherePK().
+		Where("email_verification_sent_at IS NULL OR email_verification_sent_at <= ?", now.Add(-period)).
+		Returning("email_verification_sent_at").
+		Scan(context.Background())
+}
+
+// UpdateVerificationSent saves only the time the verification email was sent.
+func (r AuthRepository) UpdateVerificationSent(user *entity.User) error {
+	_, err := r.pgDb.NewUpdate().
+		Model(user).
+		Column("email_verification_sent_at").
+		WherePK().
+		Exec(context.Background())
+	return err
+}
+
+// UpdatePassword saves the new password of the user together with the columns changing with it, provided
+// the password is still the previous one. A concurrent change of the password makes it return sql.ErrNoRows,
+// so a password can be replaced only once from the same state. A verified email stays verified.
+func (r AuthRepository) UpdatePassword(user *entity.User, previousPassword string) error {
+	var previous interface{}
+	if previousPassword != "" {
+		previous = previousPassword
+	}
+
+	return r.pgDb.NewUpdate().
+		Model(user).
+		Set("password = ?", user.Password).
+		Set("email_verified_at = COALESCE(email_verified_at, ?)", user.EmailVerifiedAt).
+		Set("failed_logins = ?", user.FailedLogins).
+		Set("locked_until = ?", user.LockedUntil).
+		Set("updated_at = ?", user.UpdatedAt).
+		WherePK().
+		Where("password IS NOT DISTINCT FROM ?", previous).
+		Returning("email_verified_at").
+		Scan(context.Background())
+}
+
+// UpdateProfile saves only the columns the user edits in their profile.
+func (r AuthRepository) UpdateProfile(user *entity.User) error {
+	_, err := r.pgDb.NewUpdate().
+		Model(user).
+		Column("name", "email", "email_verified_at", "email_verification_sent_at", "locale", "timezone", "updated_at").
+		WherePK().
+		Exec(context.Background())
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrEmailUniqConstraint
+		}
+		return err
+	}
+
+	return nil
+}
+
+// UpdateEmailVerified saves only the time the email was verified.
+func (r AuthRepository) UpdateEmailVerified(user *entity.User) error {
+	_, err := r.pgDb.NewUpdate().
+		Model(user).
+		Column("email_verified_at", "updated_at").
+		WherePK().
+		Exec(context.Background())
+	return err
+}
+
+// UpdateRole saves only the role of the user.
+func (r AuthRepository) UpdateRole(user *entity.User) error {
+	_, err := r.pgDb.NewUpdate().
+		Model(user).
+		Column("role", "updated_at").
+		WherePK().
+		Exec(context.Background())
+	return err
+}
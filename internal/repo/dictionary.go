@@ -0,0 +1,135 @@
+package repo
+
+import (
+	"context"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+const wordsBatchSize = 1000
+
+type DictionaryRepository struct {
+	pgDb *bun.DB
+}
+
+func NewDictionaryRepository(pgDb *bun.DB) *DictionaryRepository {
+	return &DictionaryRepository{pgDb: pgDb}
+}
+
+// FindWord returns the dictionary entry of the word whatever its flags are.
+func (r *DictionaryRepository) FindWord(word string) (*entity.Word, error) {
+	ctx := context.Background()
+	w := &entity.Word{}
+
+	errSelect := r.pgDb.NewSelect().
+		Model(w).
+		Where("word = ?", word).
+		Scan(ctx)
+	if errSelect != nil {
+		return nil, errSelect
+	}
+
+	return w, nil
+}
+
+// wordFlagColumns are the columns of a word editable after it is added, the word itself never changes.
+var wordFlagColumns = []string{"is_enabled", "is_answer", "is_offensive", "difficulty"}
+
+func (r *DictionaryRepository) UpdateWord(word *entity.Word) error {
+	ctx := context.Background()
+
+	_, errUpdate := r.pgDb.NewUpdate().
+		Model(word).
+		Column(wordFlagColumns...).
+		WherePK().
+		Exec(ctx)
+
+	return errUpdate
+}
+
+// FindExistingWords tells which of the words are in the dictionary already.
+func (r *DictionaryRepository) FindExistingWords(words []string) (map[string]bool, error) {
+	ctx := context.Background()
+	existing := make(map[string]bool)
+
+	for start := 0; start < len(words); start += wordsBatchSize {
+		batch := words[start:min(start+wordsBatchSize, len(words))]
+
+		var found []string
+		errSelect := r.pgDb.NewSelect().
+			Model((*entity.Word)(nil)).
+			Column("word").
+			Where("word IN (?)", bun.In(batch)).
+			Scan(ctx, &found)
+		if errSelect != nil {
+			return nil, errSelect
+		}
+		for _, word := range found {
+			existing[word] = true
+		}
+	}
+
+	return existing, nil
+}
+
+// SaveFoundWords adds the words missing from the dictionary and records that the source vouches for
+// every one of them. Words already in the dictionary keep their flags.
+func (r *DictionaryRepository) SaveFoundWords(source string, words []*entity.Word) error {
+	ctx := context.Background()
+	now := time.Now()
+
+	return r.pgDb.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for start := 0; start < len(words); start += wordsBatchSize {
+			batch := words[start:min(start+wordsBatchSize, len(words))]
+
+			_, errInsert := tx.NewInsert().Model(&batch).Ignore().Returning("NULL").Exec(ctx)
+			if errInsert != nil {
+				return errInsert
+			}
+
+			texts := make([]string, 0, len(batch))
+			for _, word := range batch {
+				texts = append(texts, word.Word)
+			}
+			_, errInsert = tx.NewRaw(
+				`INSERT INTO word_sources (word_id, source, first_seen_at, last_seen_at)
+				SELECT id, ?, ?, ? FROM words WHERE word IN (?)
+				ON CONFLICT (word_id, source) DO UPDATE SET last_seen_at = EXCLUDED.last_seen_at`,
+				source, now, now, bun.In(texts),
+			).Exec(ctx)
+			if errInsert != nil {
+				return errInsert
+			}
+		}
+
+		return nil
+	})
+}
+
+// FindSingleSourceWords returns the enabled words found in a single source only, optionally in the given one.
+func (r *DictionaryRepository) FindSingleSourceWords(source string) ([]*entity.WordSource, error) {
+	ctx := context.Background()
+	var sources []*entity.WordSource
+
+	singleSource := r.pgDb.NewSelect().
+		Model((*entity.WordSource)(nil)).
+		Column("word_id").
+		Group("word_id").
+		Having("COUNT(*) = 1")
+	query := r.pgDb.NewSelect().
+		Model(&sources).
+		Relation("Word").
+		Where("?TableAlias.word_id IN (?)", singleSource).
+		Where("word.is_enabled").
+		OrderExpr("?TableAlias.source ASC, word.word ASC")
+	if source != "" {
+		query.Where("?TableAlias.source = ?", source)
+	}
+
+	if errSelect := query.Scan(ctx); errSelect != nil {
+		return nil, errSelect
+	}
+
+	return sources, nil
+}
@@ -0,0 +1,138 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrDuelNotFound     = errors.New("duel not found")
+	ErrDuelGameNotFound = errors.New("duel game not found")
+)
+
+type DuelRepository struct {
+	pgDb *bun.DB
+}
+
+func NewDuelRepository(pgDb *bun.DB) *DuelRepository {
+	return &DuelRepository{pgDb: pgDb}
+}
+
+// CreateDuel creates a duel waiting for an opponent together with the game of its creator.
+func (r *DuelRepository) CreateDuel(word *entity.Word, creator *entity.User, guessLimit int8) (*entity.Duel, error) {
+	ctx := context.Background()
+	duel := entity.NewDuel(word, creator, guessLimit)
+
+	err := r.pgDb.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, errInsert := tx.NewInsert().Model(duel).Returning("id").Exec(ctx)
+		if errInsert != nil {
+			return errInsert
+		}
+
+		game := entity.NewDuelGame(duel, creator)
+		_, errInsert = tx.NewInsert().Model(game).Returning("id").Exec(ctx)
+		if errInsert != nil {
+			return errInsert
+		}
+		duel.Games = []*entity.Game{game}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return duel, nil
+}
+
+// JoinDuel makes the user the opponent of the duel with the invite code and creates the user's game.
+func (r *DuelRepository) JoinDuel(inviteCode string, opponent *entity.User) (*entity.Duel, error) {
+	ctx := context.Background()
+	duel := &entity.Duel{}
+
+	err := r.pgDb.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		errSelect := tx.NewSelect().
+			Model(duel).
+			Relation("Word").
+			Where("invite_code = ?", inviteCode).
+			For("UPDATE OF ?TableAlias").
+			Scan(ctx)
+		if errSelect != nil {
+			if errors.Is(errSelect, sql.ErrNoRows) {
+				return ErrDuelNotFound
+			}
+			return errSelect
+		}
+
+		if errJoin := duel.Join(opponent); errJoin != nil {
+			return errJoin
+		}
+		_, errUpdate := tx.NewUpdate().Model(duel).WherePK().Exec(ctx)
+		if errUpdate != nil {
+			return errUpdate
+		}
+
+		_, errInsert := tx.NewInsert().Model(entity.NewDuelGame(duel, opponent)).Returning("id").Exec(ctx)
+
+		return errInsert
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindDuel(opponent, duel.Id)
+}
+
+// FindDuel returns the duel with both games if the user takes part in it.
+func (r *DuelRepository) FindDuel(currentUser *entity.User, id int64) (*entity.Duel, error) {
+	ctx := context.Background()
+	duel := &entity.Duel{}
+
+	errSelect := r.pgDb.NewSelect().
+		Model(duel).
+		Relation("Word").
+		Relation("Creator").
+		Relation("Opponent").
+		Relation("Games", func(sq *bun.SelectQuery) *bun.SelectQuery {
+			return sq.Relation("Word").Relation("Guesses", orderGuesses).Relation("Guesses.Word")
+		}).
+		Where("?TableAlias.id = ?", id).
+		WhereGroup(" AND ", func(sq *bun.SelectQuery) *bun.SelectQuery {
+			return sq.
+				Where("?TableAlias.creator_id = ?", currentUser.Id).
+				WhereOr("?TableAlias.opponent_id = ?", currentUser.Id)
+		}).
+		Scan(ctx)
+	if errSelect != nil {
+		if errors.Is(errSelect, sql.ErrNoRows) {
+			return nil, ErrDuelNotFound
+		}
+		return nil, errSelect
+	}
+
+	return duel, nil
+}
+
+// recordDuelGame resolves the duel of a just finished game within the transaction that finished it.
+func recordDuelGame(ctx context.Context, tx bun.Tx, game *entity.Game) error {
+	duel := &entity.Duel{}
+	errSelect := tx.NewSelect().
+		Model(duel).
+		Relation("Games", func(sq *bun.SelectQuery) *bun.SelectQuery {
+			return sq.Relation("Guesses")
+		}).
+		Where("?TableAlias.id = ?", game.DuelId.Int64).
+		For("UPDATE OF ?TableAlias").
+		Scan(ctx)
+	if errSelect != nil {
+		return errSelect
+	}
+
+	duel.Resolve()
+	_, errUpdate := tx.NewUpdate().Model(duel).WherePK().Exec(ctx)
+
+	return errUpdate
+}
@@ -0,0 +1,27 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jackc/pgerrcode"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+func isUniqueViolation(err error) bool {
+	var pgErr pgdriver.Error
+	return errors.As(err, &pgErr) && pgErr.IntegrityViolation() && pgErr.Field('C') == pgerrcode.UniqueViolation
+}
+
+// isSerializationFailure tells a transaction that has to be retried, e.g. a repeatable read one
+// which tried to change a row changed by a concurrent transaction since it started.
+func isSerializationFailure(err error) bool {
+	var pgErr pgdriver.Error
+	return errors.As(err, &pgErr) && pgErr.Field('C') == pgerrcode.SerializationFailure
+}
+
+func ignoreNoRows(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return err
+}
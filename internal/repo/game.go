@@ -9,16 +9,24 @@ import (
 	"time"
 )
 
+// maxTxAttempts is how many times a transaction conflicting with a concurrent one is tried.
+const maxTxAttempts = 3
+
 var (
-	ErrCurrentGameNotFound = errors.New("current game not found")
+	ErrCurrentGameNotFound           = errors.New("current game not found")
+	ErrDailyGameNotFound             = errors.New("daily game not found")
+	ErrDailyGameUniqConstraint       = errors.New("daily game already exists")
+	ErrDailyPuzzleDateUniqConstraint = errors.New("daily puzzle for this date already exists")
 )
 
 type GameRepository struct {
 	pgDb *bun.DB
+	// location is the timezone of the daily puzzle, the leaderboard periods follow its calendar.
+	location *time.Location
 }
 
-func NewGameRepository(pgDb *bun.DB) *GameRepository {
-	return &GameRepository{pgDb: pgDb}
+func NewGameRepository(pgDb *bun.DB, location *time.Location) *GameRepository {
+	return &GameRepository{pgDb: pgDb, location: location}
 }
 
 func (r *GameRepository) FindCurrentGame(currentUser *entity.User) (*entity.Game, error) {
@@ -36,26 +44,35 @@ func (r *GameRepository) FindCurrentGame(currentUser *entity.User) (*entity.Game
 		_ = tx.Rollback()
 		return nil, errSelect
 	}
-	_ = tx.Commit()
+	if errCommit := tx.Commit(); errCommit != nil {
+		return nil, errCommit
+	}
 
 	return game, nil
 }
 
+// IsCurrentGameExists checks for an unfinished practice game only: daily and duel games
+// are played alongside it.
 func (r *GameRepository) IsCurrentGameExists(currentUser *entity.User) (bool, error) {
 	ctx := context.Background()
 
 	isExists, errSelect := r.pgDb.NewSelect().
 		Table("games").
 		Where("user_id = ?", currentUser.Id).
+		Where("mode = ?", entity.GameModePractice).
 		Where("is_playing = ?", true).
 		Exists(ctx)
 
 	return isExists, errSelect
 }
 
-func (r *GameRepository) CreateGame(word *entity.Word, currentUser *entity.User) (*entity.Game, error) {
+func (r *GameRepository) CreateGame(
+	word *entity.Word,
+	currentUser *entity.User,
+	options entity.GameOptions,
+) (*entity.Game, error) {
 	ctx := context.Background()
-	game := entity.NewGame(word, currentUser)
+	game := entity.NewGame(word, currentUser, options)
 
 	_, errInsert := r.pgDb.NewInsert().Model(game).Returning("id").Exec(ctx)
 	if errInsert != nil {
@@ -65,18 +82,22 @@ func (r *GameRepository) CreateGame(word *entity.Word, currentUser *entity.User)
 	return game, nil
 }
 
-func (r *GameRepository) FindRandomWord() (*entity.Word, error) {
+func (r *GameRepository) FindRandomWord(length int) (*entity.Word, error) {
 	ctx := context.Background()
-	word := entity.Word{}
+	word := &entity.Word{}
 
-	errSelect := r.pgDb.
-		NewRaw("SELECT * FROM words ORDER BY RANDOM() LIMIT ?", 1).
-		Scan(ctx, &word.Id, &word.Word, &word.CreatedAt)
+	errSelect := r.pgDb.NewSelect().
+		Model(word).
+		Apply(answerWords).
+		Where("length = ?", length).
+		OrderExpr("RANDOM()").
+		Limit(1).
+		Scan(ctx)
 	if errSelect != nil {
 		return nil, errSelect
 	}
 
-	return &word, nil
+	return word, nil
 }
 
 func (r *GameRepository) FindWord(word string) (*entity.Word, error) {
@@ -87,6 +108,7 @@ func (r *GameRepository) FindWord(word string) (*entity.Word, error) {
 		NewSelect().
 		Model(&w).
 		Where("word = ?", word).
+		Where("is_enabled").
 		Scan(ctx)
 	if errSelect != nil {
 		return nil, errSelect
@@ -96,69 +118,340 @@ func (r *GameRepository) FindWord(word string) (*entity.Word, error) {
 }
 
 func (r *GameRepository) AddGuessForCurrentGame(currentUser *entity.User, word *entity.Word) (*entity.Game, error) {
+	game, err := r.addGuess(word, func(sq *bun.SelectQuery, model *entity.Game) *bun.SelectQuery {
+		return getSelectQueryFindCurrentGame(sq, model, currentUser.Id)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCurrentGameNotFound
+	}
+
+	return game, err
+}
+
+func (r *GameRepository) AddGuessForDailyGame(
+	currentUser *entity.User,
+	puzzle *entity.DailyPuzzle,
+	word *entity.Word,
+) (*entity.Game, error) {
+	game, err := r.addGuess(word, func(sq *bun.SelectQuery, model *entity.Game) *bun.SelectQuery {
+		return getSelectQueryFindDailyGame(sq, model, currentUser.Id, puzzle.Id).
+			Where("is_playing = ?", true)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDailyGameNotFound
+	}
+
+	return game, err
+}
+
+func (r *GameRepository) AddGuessForDuelGame(
+	currentUser *entity.User,
+	duelId int64,
+	word *entity.Word,
+) (*entity.Game, error) {
+	game, err := r.addGuess(word, func(sq *bun.SelectQuery, model *entity.Game) *bun.SelectQuery {
+		return sq.
+			Model(model).
+			Relation("Word").
+			Relation("Guesses", orderGuesses).
+			Relation("Guesses.Word").
+			Where("?TableAlias.user_id = ?", currentUser.Id).
+			Where("?TableAlias.duel_id = ?", duelId).
+			Where("?TableAlias.is_playing = ?", true).
+			Where("?TableAlias.duel_id IN (?)", sq.NewSelect().
+				Model((*entity.Duel)(nil)).
+				Column("id").
+				Where("status = ?", entity.DuelStatusPlaying))
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDuelGameNotFound
+	}
+
+	return game, err
+}
+
+func (r *GameRepository) SurrenderCurrentGame(currentUser *entity.User) (*entity.Game, error) {
+	game, err := r.playGame(
+		func(sq *bun.SelectQuery, model *entity.Game) *bun.SelectQuery {
+			return getSelectQueryFindCurrentGame(sq, model, currentUser.Id)
+		},
+		func(ctx context.Context, tx bun.Tx, game *entity.Game) error {
+			game.Surrender()
+			return nil
+		},
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCurrentGameNotFound
+	}
+
+	return game, err
+}
+
+func (r *GameRepository) addGuess(
+	word *entity.Word,
+	selectGame func(sq *bun.SelectQuery, model *entity.Game) *bun.SelectQuery,
+) (*entity.Game, error) {
+	return r.playGame(selectGame, func(ctx context.Context, tx bun.Tx, game *entity.Game) error {
+		guess, errGuess := game.AddGuess(word)
+		if errGuess != nil {
+			return errGuess
+		}
+		_, errInsert := tx.NewInsert().Model(guess).Returning("id").Exec(ctx)
+
+		return errInsert
+	})
+}
+
+// playGame selects a game, applies a move to it and saves the game in a single transaction.
+// When the move finishes the game, the stats of its player are updated in the same transaction.
+// The transaction is retried when it conflicts with a concurrent one, e.g. when both players
+// of a duel finish at the same moment and both resolve the duel.
+func (r *GameRepository) playGame(
+	selectGame func(sq *bun.SelectQuery, model *entity.Game) *bun.SelectQuery,
+	move func(ctx context.Context, tx bun.Tx, game *entity.Game) error,
+) (*entity.Game, error) {
+	for attempt := 1; ; attempt++ {
+		game, err := r.playGameOnce(selectGame, move)
+		if err == nil || !isSerializationFailure(err) || attempt >= maxTxAttempts {
+			return game, err
+		}
+	}
+}
+
+func (r *GameRepository) playGameOnce(
+	selectGame func(sq *bun.SelectQuery, model *entity.Game) *bun.SelectQuery,
+	move func(ctx context.Context, tx bun.Tx, game *entity.Game) error,
+) (*entity.Game, error) {
 	ctx := context.Background()
 	tx, err := r.pgDb.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
 	if err != nil {
 		return nil, err
 	}
 
-	currentGame := &entity.Game{}
-	sq := tx.NewSelect()
-	errSelect := getSelectQueryFindCurrentGame(sq, currentGame, currentUser.Id).Scan(ctx)
+	game := &entity.Game{}
+	errSelect := selectGame(tx.NewSelect(), game).Scan(ctx)
 	if errSelect != nil {
 		_ = tx.Rollback()
-		if currentGame.Id == 0 {
-			return nil, ErrCurrentGameNotFound
-		}
 		return nil, errSelect
 	}
 
-	guess := currentGame.AddGuess(word)
-	_, errInsert := tx.NewInsert().Model(guess).Returning("id").Exec(ctx)
-	if errInsert != nil {
+	if errMove := move(ctx, tx, game); errMove != nil {
 		_ = tx.Rollback()
-		return nil, errInsert
+		return nil, errMove
 	}
 
-	if currentGame.IsPlaying == false {
-		_, errUpdate := tx.NewUpdate().Model(currentGame).Where("id = ?", currentGame.Id).Exec(ctx)
-		if errUpdate != nil {
+	_, errUpdate := tx.NewUpdate().Model(game).Where("id = ?", game.Id).Exec(ctx)
+	if errUpdate != nil {
+		_ = tx.Rollback()
+		return nil, errUpdate
+	}
+
+	if game.IsPlaying == false {
+		errStats := recordFinishedGame(ctx, tx, game)
+		if errStats != nil {
+			_ = tx.Rollback()
+			return nil, errStats
+		}
+
+		errLeaderboard := recordLeaderboardGame(ctx, tx, game, r.location)
+		if errLeaderboard != nil {
 			_ = tx.Rollback()
-			return nil, errUpdate
+			return nil, errLeaderboard
+		}
+
+		if game.DuelId.Valid {
+			errDuel := recordDuelGame(ctx, tx, game)
+			if errDuel != nil {
+				_ = tx.Rollback()
+				return nil, errDuel
+			}
 		}
 	}
 
-	_ = tx.Commit()
+	if errCommit := tx.Commit(); errCommit != nil {
+		return nil, errCommit
+	}
 
-	return currentGame, nil
+	return game, nil
 }
 
-func (r *GameRepository) SaveWords(words []string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
+func (r *GameRepository) FindDailyPuzzle(date time.Time) (*entity.DailyPuzzle, error) {
+	ctx := context.Background()
+	puzzle := &entity.DailyPuzzle{}
 
-	var wordEntities []*entity.Word
-	for _, word := range words {
-		wordEntities = append(wordEntities, entity.NewWord(word))
+	errSelect := r.pgDb.NewSelect().
+		Model(puzzle).
+		Relation("Word").
+		Where("date = ?", date.Format(time.DateOnly)).
+		Scan(ctx)
+	if errSelect != nil {
+		return nil, errSelect
 	}
-	_, err := r.pgDb.NewInsert().
-		Model(&wordEntities).
-		Ignore().
-		Exec(ctx)
-	if err != nil {
-		return err
+
+	return puzzle, nil
+}
+
+// FindWordForDate deterministically picks a word for a date that has no scheduled puzzle yet,
+// so every app instance resolves the same date to the same word.
+func (r *GameRepository) FindWordForDate(date time.Time, length int) (*entity.Word, error) {
+	ctx := context.Background()
+	word := &entity.Word{}
+
+	errSelect := r.pgDb.NewSelect().
+		Model(word).
+		Apply(answerWords).
+		Where("length = ?", length).
+		OrderExpr("md5(word || ?)", date.Format(time.DateOnly)).
+		Limit(1).
+		Scan(ctx)
+	if errSelect != nil {
+		return nil, errSelect
 	}
 
-	return nil
+	return word, nil
+}
+
+func (r *GameRepository) CreateDailyPuzzle(date time.Time, word *entity.Word) (*entity.DailyPuzzle, error) {
+	ctx := context.Background()
+	puzzle := entity.NewDailyPuzzle(date, word)
+
+	_, errInsert := r.pgDb.NewInsert().Model(puzzle).Returning("id").Exec(ctx)
+	if errInsert != nil {
+		if isUniqueViolation(errInsert) {
+			return nil, ErrDailyPuzzleDateUniqConstraint
+		}
+		return nil, errInsert
+	}
+	puzzle.Word = word
+
+	return puzzle, nil
+}
+
+func (r *GameRepository) FindDailyGame(currentUser *entity.User, puzzle *entity.DailyPuzzle) (*entity.Game, error) {
+	ctx := context.Background()
+	game := &entity.Game{}
+
+	errSelect := getSelectQueryFindDailyGame(r.pgDb.NewSelect(), game, currentUser.Id, puzzle.Id).Scan(ctx)
+	if errSelect != nil {
+		return nil, errSelect
+	}
+
+	return game, nil
+}
+
+func (r *GameRepository) CreateDailyGame(
+	puzzle *entity.DailyPuzzle,
+	currentUser *entity.User,
+	options entity.GameOptions,
+) (*entity.Game, error) {
+	ctx := context.Background()
+	game := entity.NewDailyGame(puzzle, currentUser, options)
+
+	_, errInsert := r.pgDb.NewInsert().Model(game).Returning("id").Exec(ctx)
+	if errInsert != nil {
+		if isUniqueViolation(errInsert) {
+			return nil, ErrDailyGameUniqConstraint
+		}
+		return nil, errInsert
+	}
+
+	return game, nil
+}
+
+// FindGames returns at most limit games of the user matching the filter, newest first,
+// starting right after the game with the beforeId id (or from the newest one when beforeId is 0).
+func (r *GameRepository) FindGames(
+	currentUser *entity.User,
+	filter entity.GameFilter,
+	beforeId int64,
+	limit int,
+) ([]*entity.Game, error) {
+	ctx := context.Background()
+	games := make([]*entity.Game, 0, limit)
+
+	sq := r.pgDb.NewSelect().
+		Model(&games).
+		Relation("Word").
+		Relation("DailyPuzzle").
+		Relation("Guesses", orderGuesses).
+		Relation("Guesses.Word").
+		Where("?TableAlias.user_id = ?", currentUser.Id)
+	if beforeId > 0 {
+		sq.Where("?TableAlias.id < ?", beforeId)
+	}
+	if filter.IsPlaying.Valid {
+		sq.Where("?TableAlias.is_playing = ?", filter.IsPlaying.Bool)
+	}
+	if filter.IsWon.Valid {
+		sq.Where("?TableAlias.is_won = ?", filter.IsWon.Bool)
+	}
+	if filter.Mode != "" {
+		sq.Where("?TableAlias.mode = ?", filter.Mode)
+	}
+	if !filter.From.IsZero() {
+		sq.Where("?TableAlias.created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		sq.Where("?TableAlias.created_at < ?", filter.To)
+	}
+
+	errSelect := sq.OrderExpr("?TableAlias.id DESC").Limit(limit).Scan(ctx)
+	if errSelect != nil {
+		return nil, errSelect
+	}
+
+	return games, nil
+}
+
+func (r *GameRepository) FindGame(currentUser *entity.User, id int64) (*entity.Game, error) {
+	ctx := context.Background()
+	game := &entity.Game{}
+
+	errSelect := r.pgDb.NewSelect().
+		Model(game).
+		Relation("Word").
+		Relation("DailyPuzzle").
+		Relation("Guesses", orderGuesses).
+		Relation("Guesses.Word").
+		Where("?TableAlias.id = ?", id).
+		Where("?TableAlias.user_id = ?", currentUser.Id).
+		Scan(ctx)
+	if errSelect != nil {
+		return nil, errSelect
+	}
+
+	return game, nil
 }
 
 func getSelectQueryFindCurrentGame(sq *bun.SelectQuery, model *entity.Game, userId int64) *bun.SelectQuery {
 	sq.
 		Model(model).
 		Relation("Word").
-		Relation("Guesses").
+		Relation("Guesses", orderGuesses).
 		Relation("Guesses.Word").
 		Where("user_id = ?", userId).
+		Where("mode = ?", entity.GameModePractice).
 		Where("is_playing = ?", true)
 	return sq
 }
+
+func getSelectQueryFindDailyGame(sq *bun.SelectQuery, model *entity.Game, userId int64, puzzleId int64) *bun.SelectQuery {
+	sq.
+		Model(model).
+		Relation("Word").
+		Relation("DailyPuzzle").
+		Relation("Guesses", orderGuesses).
+		Relation("Guesses.Word").
+		Where("user_id = ?", userId).
+		Where("daily_puzzle_id = ?", puzzleId)
+	return sq
+}
+
+// answerWords narrows a query to the words that can be the secret word of a game.
+func answerWords(sq *bun.SelectQuery) *bun.SelectQuery {
+	return sq.Where("is_answer").Where("is_enabled").Where("NOT is_offensive")
+}
+
+func orderGuesses(sq *bun.SelectQuery) *bun.SelectQuery {
+	return sq.OrderExpr("?TableAlias.id ASC")
+}
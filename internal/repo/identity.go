@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+var ErrIdentityUniqConstraint = errors.New("identity is linked already")
+
+type IdentityRepository struct {
+	pgDb *bun.DB
+}
+
+func NewIdentityRepository(pgDb *bun.DB) *IdentityRepository {
+	return &IdentityRepository{pgDb: pgDb}
+}
+
+// CreateExternalLogin stores the started login. The logins never completed are trimmed on the way.
+func (r *IdentityRepository) CreateExternalLogin(login *entity.ExternalLogin) error {
+	ctx := context.Background()
+
+	return r.pgDb.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, errDelete := tx.NewDelete().
+			Model((*entity.ExternalLogin)(nil)).
+			Where("expires_at <= ?", time.Now()).
+			Exec(ctx)
+		if errDelete != nil {
+			return errDelete
+		}
+
+		_, err := tx.NewInsert().Model(login).Exec(ctx)
+		return err
+	})
+}
+
+// UseExternalLogin marks the login with the state hash as used and returns it. A login completes only once:
+// sql.ErrNoRows is returned when it is unknown or was used before.
+func (r *IdentityRepository) UseExternalLogin(stateHash string) (*entity.ExternalLogin, error) {
+	login := &entity.ExternalLogin{}
+	err := r.pgDb.NewUpdate().
+		Model(login).
+		Set("used_at = ?", time.Now()).
+		Where("state_hash = ?", stateHash).
+		Where("used_at IS NULL").
+		Returning("*").
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return login, nil
+}
+
+func (r *IdentityRepository) FindIdentity(provider string, subject string) (*entity.UserIdentity, error) {
+	identity := &entity.UserIdentity{}
+	err := r.pgDb.NewSelect().
+		Model(identity).
+		Relation("User").
+		Where("?TableAlias.provider = ?", provider).
+		Where("?TableAlias.subject = ?", subject).
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+// UpdateIdentityLogin saves the time of the login and the email the provider knows the user by now.
+func (r *IdentityRepository) UpdateIdentityLogin(identity *entity.UserIdentity) error {
+	_, err := r.pgDb.NewUpdate().
+		Model(identity).
+		Column("email", "last_login_at").
+		WherePK().
+		Exec(context.Background())
+	return err
+}
+
+// CreateUserWithIdentity registers the user together with their identity at the provider.
+func (r *IdentityRepository) CreateUserWithIdentity(user *entity.User, identity *entity.UserIdentity) error {
+	ctx := context.Background()
+
+	return r.pgDb.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(user).Returning("id").Exec(ctx); err != nil {
+			if isUniqueViolation(err) {
+				return ErrEmailUniqConstraint
+			}
+			return err
+		}
+
+		identity.UserId = user.Id
+		return insertIdentity(ctx, tx, identity)
+	})
+}
+
+// LinkIdentity links the identity to the user. Of the user, only what claiming the account changes is saved:
+// the password, the email verification and the update time.
+func (r *IdentityRepository) LinkIdentity(user *entity.User, identity *entity.UserIdentity) error {
+	ctx := context.Background()
+
+	return r.pgDb.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().
+			Model(user).
+			Column("password", "email_verified_at", "updated_at").
+			WherePK().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		identity.UserId = user.Id
+		return insertIdentity(ctx, tx, identity)
+	})
+}
+
+func insertIdentity(ctx context.Context, tx bun.Tx, identity *entity.UserIdentity) error {
+	_, err := tx.NewInsert().Model(identity).Returning("id").Exec(ctx)
+	if err != nil && isUniqueViolation(err) {
+		return ErrIdentityUniqConstraint
+	}
+
+	return err
+}
@@ -0,0 +1,173 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type LeaderboardRepository struct {
+	pgDb *bun.DB
+	// location is the timezone of the daily puzzle, the leaderboard periods follow its calendar.
+	location *time.Location
+}
+
+func NewLeaderboardRepository(pgDb *bun.DB, location *time.Location) *LeaderboardRepository {
+	return &LeaderboardRepository{pgDb: pgDb, location: location}
+}
+
+// periodRankings describes how each kind of periodic leaderboard scores and orders its entries.
+var periodRankings = map[string]struct {
+	score string
+	where string
+	order string
+}{
+	entity.LeaderboardKindWins: {
+		score: "e.wins",
+		where: "e.wins > 0",
+		order: "e.wins DESC",
+	},
+	entity.LeaderboardKindAvgGuesses: {
+		score: "e.win_guesses::float / e.wins",
+		where: "e.wins > 0",
+		order: "e.win_guesses::float / e.wins ASC",
+	},
+	entity.LeaderboardKindStreak: {
+		score: "e.max_streak",
+		where: "e.max_streak > 0",
+		order: "e.max_streak DESC",
+	},
+}
+
+// FindPeriodRankings returns the top rankings of a periodic leaderboard followed by the ranking of
+// the current user when they are outside of the top.
+func (r *LeaderboardRepository) FindPeriodRankings(
+	currentUser *entity.User,
+	kind string,
+	period string,
+	periodStart time.Time,
+	limit int,
+) ([]*entity.Ranking, error) {
+	ctx := context.Background()
+	ranking := periodRankings[kind]
+	rankings := make([]*entity.Ranking, 0, limit+1)
+
+	errSelect := r.pgDb.NewRaw(`
+		WITH ranked AS (
+			SELECT e.user_id, u.name, `+ranking.score+` AS score, 0 AS solve_seconds,
+				RANK() OVER (ORDER BY `+ranking.order+`) AS rank
+			FROM leaderboard_entries AS e
+			JOIN users AS u ON u.id = e.user_id
+			WHERE e.period = ? AND e.period_start = ? AND `+ranking.where+` AND u.banned_at IS NULL
+		)
+		SELECT * FROM ranked WHERE rank <= ? OR user_id = ? ORDER BY rank, user_id`,
+		period, periodStart.Format(time.DateOnly), limit, currentUser.Id,
+	).Scan(ctx, &rankings)
+	if err := ignoreNoRows(errSelect); err != nil {
+		return nil, err
+	}
+
+	return rankings, nil
+}
+
+// FindDailyRankings ranks players who solved the daily puzzle by guesses used, then by solve time.
+func (r *LeaderboardRepository) FindDailyRankings(
+	currentUser *entity.User,
+	puzzle *entity.DailyPuzzle,
+	limit int,
+) ([]*entity.Ranking, error) {
+	ctx := context.Background()
+	rankings := make([]*entity.Ranking, 0, limit+1)
+
+	errSelect := r.pgDb.NewRaw(`
+		WITH ranked AS (
+			SELECT d.user_id, u.name, d.guesses AS score, d.solve_seconds,
+				RANK() OVER (ORDER BY d.guesses ASC, d.solve_seconds ASC) AS rank
+			FROM daily_results AS d
+			JOIN users AS u ON u.id = d.user_id
+			WHERE d.daily_puzzle_id = ? AND d.is_won AND u.banned_at IS NULL
+		)
+		SELECT * FROM ranked WHERE rank <= ? OR user_id = ? ORDER BY rank, user_id`,
+		puzzle.Id, limit, currentUser.Id,
+	).Scan(ctx, &rankings)
+	if err := ignoreNoRows(errSelect); err != nil {
+		return nil, err
+	}
+
+	return rankings, nil
+}
+
+// RebuildLeaderboards recalculates leaderboard counters and daily results from the raw game history.
+func (r *LeaderboardRepository) RebuildLeaderboards() error {
+	ctx := context.Background()
+	tx, err := r.pgDb.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return err
+	}
+
+	for _, model := range []any{(*entity.LeaderboardEntry)(nil), (*entity.DailyResult)(nil)} {
+		_, errDelete := tx.NewDelete().Model(model).Where("TRUE").Exec(ctx)
+		if errDelete != nil {
+			_ = tx.Rollback()
+			return errDelete
+		}
+	}
+
+	var games []*entity.Game
+	errSelect := tx.NewSelect().
+		Model(&games).
+		Relation("Guesses").
+		Apply(countedGames).
+		OrderExpr("?TableAlias.updated_at ASC, ?TableAlias.id ASC").
+		Scan(ctx)
+	if errSelect != nil {
+		_ = tx.Rollback()
+		return errSelect
+	}
+
+	for _, game := range games {
+		if errRecord := recordLeaderboardGame(ctx, tx, game, r.location); errRecord != nil {
+			_ = tx.Rollback()
+			return errRecord
+		}
+	}
+
+	return tx.Commit()
+}
+
+// recordLeaderboardGame adds a just finished game to the leaderboard counters of every period
+// within the transaction that finished it.
+func recordLeaderboardGame(ctx context.Context, tx bun.Tx, game *entity.Game, location *time.Location) error {
+	for _, period := range entity.LeaderboardPeriods {
+		entry := entity.NewLeaderboardEntry(period, game, location)
+		_, errInsert := tx.NewInsert().
+			Model(entry).
+			On("CONFLICT (period, period_start, user_id) DO NOTHING").
+			Exec(ctx)
+		if errInsert != nil {
+			return errInsert
+		}
+
+		errSelect := tx.NewSelect().Model(entry).WherePK().For("UPDATE").Scan(ctx)
+		if errSelect != nil {
+			return errSelect
+		}
+
+		entry.RecordGame(game)
+		_, errUpdate := tx.NewUpdate().Model(entry).WherePK().Exec(ctx)
+		if errUpdate != nil {
+			return errUpdate
+		}
+	}
+
+	if game.DailyPuzzleId.Valid {
+		_, errInsert := tx.NewInsert().Model(entity.NewDailyResult(game)).Exec(ctx)
+		if errInsert != nil {
+			return errInsert
+		}
+	}
+
+	return nil
+}
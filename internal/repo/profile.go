@@ -0,0 +1,152 @@
+package repo
+
+import (
+	"context"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// ProfileRepository gathers the personal data of a user across the tables and erases it.
+type ProfileRepository struct {
+	pgDb *bun.DB
+}
+
+func NewProfileRepository(pgDb *bun.DB) *ProfileRepository {
+	return &ProfileRepository{pgDb: pgDb}
+}
+
+// Anonymize saves the anonymized columns of the user, unlinks their external identities and revokes the links sharing their games.
+func (r *ProfileRepository) Anonymize(user *entity.User) error {
+	ctx := context.Background()
+
+	return r.pgDb.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, errUser := tx.NewUpdate().
+			Model(user).
+			Column(
+				"name",
+				"email",
+				"email_verified_at",
+				"email_verification_sent_at",
+				"password",
+				"locale",
+				"timezone",
+				"role",
+				"deleted_at",
+				"updated_at",
+			).
+			WherePK().
+			Exec(ctx)
+		if errUser != nil {
+			return errUser
+		}
+
+		_, errIdentities := tx.NewDelete().
+			Model((*entity.UserIdentity)(nil)).
+			Where("user_id = ?", user.Id).
+			Exec(ctx)
+		if errIdentities != nil {
+			return errIdentities
+		}
+
+		_, err := tx.NewUpdate().
+			Model((*entity.ShareToken)(nil)).
+			Set("revoked_at = ?", time.Now()).
+			Where("revoked_at IS NULL").
+			Where("game_id IN (?)", tx.NewSelect().
+				Model((*entity.Game)(nil)).
+				Column("id").
+				Where("user_id = ?", user.Id)).
+			Exec(ctx)
+
+		return err
+	})
+}
+
+func (r *ProfileRepository) FindGames(user *entity.User) ([]*entity.Game, error) {
+	var games []*entity.Game
+	err := r.pgDb.NewSelect().
+		Model(&games).
+		Relation("Word").
+		Relation("Guesses", orderGuesses).
+		Relation("Guesses.Word").
+		Where("?TableAlias.user_id = ?", user.Id).
+		OrderExpr("?TableAlias.id ASC").
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return games, nil
+}
+
+func (r *ProfileRepository) FindDuels(user *entity.User) ([]*entity.Duel, error) {
+	var duels []*entity.Duel
+	err := r.pgDb.NewSelect().
+		Model(&duels).
+		Where("creator_id = ? OR opponent_id = ?", user.Id, user.Id).
+		OrderExpr("id ASC").
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return duels, nil
+}
+
+// FindStats returns the statistics of the user or nil when the user has not finished a game yet.
+func (r *ProfileRepository) FindStats(user *entity.User) (*entity.UserStats, error) {
+	var stats []*entity.UserStats
+	err := r.pgDb.NewSelect().
+		Model(&stats).
+		Where("user_id = ?", user.Id).
+		Scan(context.Background())
+	if err != nil || len(stats) == 0 {
+		return nil, err
+	}
+
+	return stats[0], nil
+}
+
+func (r *ProfileRepository) FindSessions(user *entity.User) ([]*entity.Session, error) {
+	var sessions []*entity.Session
+	err := r.pgDb.NewSelect().
+		Model(&sessions).
+		Where("user_id = ?", user.Id).
+		OrderExpr("created_at ASC").
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (r *ProfileRepository) FindShareTokens(user *entity.User) ([]*entity.ShareToken, error) {
+	var shareTokens []*entity.ShareToken
+	err := r.pgDb.NewSelect().
+		Model(&shareTokens).
+		Join("JOIN games AS g ON g.id = ?TableAlias.game_id").
+		Where("g.user_id = ?", user.Id).
+		OrderExpr("?TableAlias.id ASC").
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return shareTokens, nil
+}
+
+func (r *ProfileRepository) FindIdentities(user *entity.User) ([]*entity.UserIdentity, error) {
+	var identities []*entity.UserIdentity
+	err := r.pgDb.NewSelect().
+		Model(&identities).
+		Where("user_id = ?", user.Id).
+		OrderExpr("id ASC").
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
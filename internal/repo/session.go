@@ -0,0 +1,195 @@
+package repo
+
+import (
+	"context"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type SessionRepository struct {
+	pgDb *bun.DB
+}
+
+func NewSessionRepository(pgDb *bun.DB) *SessionRepository {
+	return &SessionRepository{pgDb: pgDb}
+}
+
+func (r *SessionRepository) CreateSession(session *entity.Session, refreshToken *entity.RefreshToken) error {
+	ctx := context.Background()
+
+	return r.pgDb.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(session).Exec(ctx); err != nil {
+			return err
+		}
+		_, err := tx.NewInsert().Model(refreshToken).Returning("id").Exec(ctx)
+		return err
+	})
+}
+
+func (r *SessionRepository) CreateRefreshToken(refreshToken *entity.RefreshToken) error {
+	_, err := r.pgDb.NewInsert().Model(refreshToken).Returning("id").Exec(context.Background())
+	return err
+}
+
+// UseRefreshToken marks the refresh token with the hash as used and returns it with its session.
+// A token can be used only once: sql.ErrNoRows is returned when it is unknown or was used before.
+func (r *SessionRepository) UseRefreshToken(tokenHash string) (*entity.RefreshToken, error) {
+	ctx := context.Background()
+	refreshToken := &entity.RefreshToken{}
+
+	errUpdate := r.pgDb.NewUpdate().
+		Model(refreshToken).
+		Set("used_at = ?", time.Now()).
+		Where("token_hash = ?", tokenHash).
+		Where("used_at IS NULL").
+		Returning("*").
+		Scan(ctx)
+	if errUpdate != nil {
+		return nil, errUpdate
+	}
+
+	refreshToken.Session = &entity.Session{}
+	errSelect := r.pgDb.NewSelect().
+		Model(refreshToken.Session).
+		Where("id = ?", refreshToken.SessionId).
+		Scan(ctx)
+	if errSelect != nil {
+		return nil, errSelect
+	}
+
+	return refreshToken, nil
+}
+
+func (r *SessionRepository) FindRefreshToken(tokenHash string) (*entity.RefreshToken, error) {
+	refreshToken := &entity.RefreshToken{}
+	err := r.pgDb.NewSelect().
+		Model(refreshToken).
+		Relation("Session").
+		Where("?TableAlias.token_hash = ?", tokenHash).
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return refreshToken, nil
+}
+
+// FindSessionByAccessJti returns the session which issued the access token, for the legacy tokens without a session id.
+func (r *SessionRepository) FindSessionByAccessJti(jti string) (*entity.Session, error) {
+	session := &entity.Session{}
+	err := r.pgDb.NewSelect().
+		Model(session).
+		Where("id = (?)", r.pgDb.NewSelect().
+			Model((*entity.RefreshToken)(nil)).
+			Column("session_id").
+			Where("access_jti = ?", jti).
+			Limit(1)).
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (r *SessionRepository) FindSession(id string) (*entity.Session, error) {
+	session := &entity.Session{}
+	err := r.pgDb.NewSelect().
+		Model(session).
+		Where("id = ?", id).
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// RevokeSession ends the session and revokes the access tokens it has issued.
+func (r *SessionRepository) RevokeSession(session *entity.Session) error {
+	ctx := context.Background()
+	now := time.Now()
+
+	return r.pgDb.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return revokeSessions(ctx, tx, now, func(q *bun.UpdateQuery) *bun.UpdateQuery {
+			return q.Where("id = ?", session.Id)
+		})
+	})
+}
+
+// RevokeUserSessions ends every session of the user and revokes the access tokens they have issued,
+// the tokens issued without a session included.
+func (r *SessionRepository) RevokeUserSessions(user *entity.User) error {
+	ctx := context.Background()
+	now := time.Now()
+
+	return r.pgDb.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, errUpdate := tx.NewUpdate().
+			Model((*entity.User)(nil)).
+			Set("sessions_revoked_at = ?", now).
+			Where("id = ?", user.Id).
+			Exec(ctx)
+		if errUpdate != nil {
+			return errUpdate
+		}
+
+		return revokeSessions(ctx, tx, now, func(q *bun.UpdateQuery) *bun.UpdateQuery {
+			return q.Where("user_id = ?", user.Id)
+		})
+	})
+}
+
+// RevokeAccessToken adds the access token to the revocation list until it expires.
+func (r *SessionRepository) RevokeAccessToken(jti string, expiresAt time.Time) error {
+	_, err := r.pgDb.NewInsert().
+		Model(&entity.RevokedToken{Jti: jti, ExpiresAt: expiresAt, RevokedAt: time.Now()}).
+		On("CONFLICT DO NOTHING").
+		Exec(context.Background())
+
+	return err
+}
+
+func (r *SessionRepository) IsTokenRevoked(jti string) (bool, error) {
+	return r.pgDb.NewSelect().
+		Model((*entity.RevokedToken)(nil)).
+		Where("jti = ?", jti).
+		Exists(context.Background())
+}
+
+func revokeSessions(ctx context.Context, tx bun.Tx, now time.Time, filter func(q *bun.UpdateQuery) *bun.UpdateQuery) error {
+	var sessionIds []string
+	errUpdate := tx.NewUpdate().
+		Model((*entity.Session)(nil)).
+		Set("revoked_at = ?", now).
+		Where("revoked_at IS NULL").
+		Apply(filter).
+		Returning("id").
+		Scan(ctx, &sessionIds)
+	if errUpdate != nil {
+		return errUpdate
+	}
+	if len(sessionIds) == 0 {
+		return nil
+	}
+
+	_, errInsert := tx.NewRaw(`
+		INSERT INTO revoked_tokens (jti, expires_at, revoked_at)
+		SELECT access_jti, access_expires_at, ?
+		FROM refresh_tokens
+		WHERE session_id IN (?) AND access_expires_at > ?
+		ON CONFLICT (jti) DO NOTHING`,
+		now, bun.In(sessionIds), now,
+	).Exec(ctx)
+	if errInsert != nil {
+		return errInsert
+	}
+
+	// The revocation list only has to outlive the access tokens, so it is trimmed on the way.
+	_, errDelete := tx.NewDelete().
+		Model((*entity.RevokedToken)(nil)).
+		Where("expires_at <= ?", now).
+		Exec(ctx)
+
+	return errDelete
+}
@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+var ErrShareTokenUniqConstraint = errors.New("the game already has an active share token")
+
+type ShareRepository struct {
+	pgDb *bun.DB
+}
+
+func NewShareRepository(pgDb *bun.DB) *ShareRepository {
+	return &ShareRepository{pgDb: pgDb}
+}
+
+func (r *ShareRepository) FindActiveShareToken(game *entity.Game) (*entity.ShareToken, error) {
+	ctx := context.Background()
+	shareToken := &entity.ShareToken{}
+
+	errSelect := r.pgDb.NewSelect().
+		Model(shareToken).
+		Where("game_id = ?", game.Id).
+		Where("revoked_at IS NULL").
+		Scan(ctx)
+	if errSelect != nil {
+		return nil, errSelect
+	}
+	shareToken.Game = game
+
+	return shareToken, nil
+}
+
+func (r *ShareRepository) CreateShareToken(game *entity.Game) (*entity.ShareToken, error) {
+	ctx := context.Background()
+	shareToken := entity.NewShareToken(game)
+
+	_, errInsert := r.pgDb.NewInsert().Model(shareToken).Returning("id").Exec(ctx)
+	if errInsert != nil {
+		if isUniqueViolation(errInsert) {
+			return nil, ErrShareTokenUniqConstraint
+		}
+		return nil, errInsert
+	}
+
+	return shareToken, nil
+}
+
+func (r *ShareRepository) RevokeShareTokens(game *entity.Game) error {
+	ctx := context.Background()
+
+	_, errUpdate := r.pgDb.NewUpdate().
+		Model((*entity.ShareToken)(nil)).
+		Set("revoked_at = ?", time.Now()).
+		Where("game_id = ?", game.Id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+
+	return errUpdate
+}
+
+// FindGameByShareToken returns the game shared with the token unless the token was revoked.
+func (r *ShareRepository) FindGameByShareToken(token string) (*entity.Game, error) {
+	ctx := context.Background()
+	game := &entity.Game{}
+
+	errSelect := r.pgDb.NewSelect().
+		Model(game).
+		Relation("Word").
+		Relation("Guesses", orderGuesses).
+		Relation("Guesses.Word").
+		Join("JOIN share_tokens AS st ON st.game_id = ?TableAlias.id").
+		Where("st.token = ?", token).
+		Where("st.revoked_at IS NULL").
+		Scan(ctx)
+	if errSelect != nil {
+		return nil, errSelect
+	}
+
+	return game, nil
+}
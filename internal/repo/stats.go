@@ -0,0 +1,117 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/uptrace/bun"
+)
+
+type StatsRepository struct {
+	pgDb *bun.DB
+}
+
+func NewStatsRepository(pgDb *bun.DB) *StatsRepository {
+	return &StatsRepository{pgDb: pgDb}
+}
+
+func (r *StatsRepository) FindStats(currentUser *entity.User) (*entity.UserStats, error) {
+	ctx := context.Background()
+	stats := &entity.UserStats{}
+
+	errSelect := r.pgDb.NewSelect().
+		Model(stats).
+		Where("user_id = ?", currentUser.Id).
+		Scan(ctx)
+	if errSelect != nil {
+		return nil, errSelect
+	}
+
+	return stats, nil
+}
+
+// RebuildStats recalculates the aggregates of every user from the raw game history
+// and returns the number of users whose stats were rebuilt.
+func (r *StatsRepository) RebuildStats() (int, error) {
+	ctx := context.Background()
+	tx, err := r.pgDb.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return 0, err
+	}
+
+	_, errDelete := tx.NewDelete().Model((*entity.UserStats)(nil)).Where("TRUE").Exec(ctx)
+	if errDelete != nil {
+		_ = tx.Rollback()
+		return 0, errDelete
+	}
+
+	var games []*entity.Game
+	errSelect := tx.NewSelect().
+		Model(&games).
+		Relation("Guesses").
+		Apply(countedGames).
+		OrderExpr("?TableAlias.user_id ASC, ?TableAlias.updated_at ASC, ?TableAlias.id ASC").
+		Scan(ctx)
+	if errSelect != nil {
+		_ = tx.Rollback()
+		return 0, errSelect
+	}
+
+	allStats := make([]*entity.UserStats, 0)
+	for _, game := range games {
+		if len(allStats) == 0 || allStats[len(allStats)-1].UserId != game.UserId {
+			allStats = append(allStats, entity.NewUserStats(game.UserId))
+		}
+		allStats[len(allStats)-1].RecordGame(game)
+	}
+
+	if len(allStats) > 0 {
+		_, errInsert := tx.NewInsert().Model(&allStats).Exec(ctx)
+		if errInsert != nil {
+			_ = tx.Rollback()
+			return 0, errInsert
+		}
+	}
+
+	if errCommit := tx.Commit(); errCommit != nil {
+		return 0, errCommit
+	}
+
+	return len(allStats), nil
+}
+
+// countedGames keeps the finished games which count towards statistics and leaderboards:
+// those finished after the stats of the player were last reset.
+func countedGames(q *bun.SelectQuery) *bun.SelectQuery {
+	return q.
+		Join("JOIN users AS u ON u.id = ?TableAlias.user_id").
+		Where("?TableAlias.is_playing = ?", false).
+		Where("u.stats_reset_at IS NULL OR ?TableAlias.updated_at > u.stats_reset_at")
+}
+
+// recordFinishedGame adds a just finished game to the stats of its player within the transaction
+// that finished it, so the aggregate never drifts from the game history.
+func recordFinishedGame(ctx context.Context, tx bun.Tx, game *entity.Game) error {
+	_, errInsert := tx.NewInsert().
+		Model(entity.NewUserStats(game.UserId)).
+		On("CONFLICT (user_id) DO NOTHING").
+		Exec(ctx)
+	if errInsert != nil {
+		return errInsert
+	}
+
+	stats := &entity.UserStats{}
+	errSelect := tx.NewSelect().
+		Model(stats).
+		Where("user_id = ?", game.UserId).
+		For("UPDATE").
+		Scan(ctx)
+	if errSelect != nil {
+		return errSelect
+	}
+
+	stats.RecordGame(game)
+	_, errUpdate := tx.NewUpdate().Model(stats).WherePK().Exec(ctx)
+
+	return errUpdate
+}
@@ -0,0 +1,393 @@
+package admin
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/repo"
+	"strconv"
+	"time"
+)
+
+const (
+	ActionBanUser     = "user.ban"
+	ActionUnbanUser   = "user.unban"
+	ActionResetStats  = "user.reset_stats"
+	ActionSetRole     = "user.set_role"
+	ActionAddWord     = "word.add"
+	ActionDisableWord = "word.disable"
+	ActionMarkAnswer  = "word.mark_answer"
+	ActionSchedule    = "daily_puzzle.schedule"
+	ActionReorder     = "daily_puzzle.reorder"
+)
+
+var (
+	ErrUserNotFound               = errors.New("user not found")
+	ErrInsufficientRole           = errors.New("users of the same or a higher role cannot be managed")
+	ErrWordNotFound               = errors.New("word not found")
+	ErrWordAlreadyExists          = errors.New("word already exists")
+	ErrWordNotPlayable            = errors.New("the word cannot be an answer of a daily puzzle")
+	ErrDailyPuzzleInPast          = errors.New("only today's and future daily puzzles can be changed")
+	ErrDailyPuzzleNotFound        = errors.New("no daily puzzle is scheduled for the date")
+	ErrDailyPuzzleAlreadyPlayed   = errors.New("the daily puzzle has already been played")
+	ErrDailyPuzzleAlreadyExists   = errors.New("a daily puzzle is already scheduled for the date")
+	ErrDailyPuzzleSameDates       = errors.New("daily puzzles of two different dates are needed")
+	ErrDailyPuzzleWrongWordLength = errors.New("the word length does not match the daily rules")
+)
+
+type IAdminRepository interface {
+	FindUser(id int64) (*entity.User, error)
+	UpdateBan(user *entity.User, entry *entity.AuditEntry) error
+	UpdateRole(user *entity.User, entry *entity.AuditEntry) error
+	ResetStats(user *entity.User, entry *entity.AuditEntry) error
+	FindWord(word string) (*entity.Word, error)
+	AddWord(word *entity.Word, entry *entity.AuditEntry) error
+	UpdateWord(word *entity.Word, entry *entity.AuditEntry) error
+	FindDailyPuzzles(from time.Time, limit int) ([]*entity.DailyPuzzle, error)
+	FindDailyPuzzle(date time.Time) (*entity.DailyPuzzle, error)
+	IsDailyPuzzlePlayed(puzzle *entity.DailyPuzzle) (bool, error)
+	CreateDailyPuzzle(puzzle *entity.DailyPuzzle, entry *entity.AuditEntry) error
+	UpdateDailyPuzzles(puzzles []*entity.DailyPuzzle, entry *entity.AuditEntry) error
+	FindAuditEntries(beforeId int64, limit int) ([]*entity.AuditEntry, error)
+}
+
+type ISessionRepository interface {
+	RevokeUserSessions(user *entity.User) error
+}
+
+// UseCase performs the changes of moderators and admins, recording each of them in the audit trail.
+type UseCase struct {
+	repository        IAdminRepository
+	sessionRepository ISessionRepository
+	dailyLocation     *time.Location
+	dailyRules        entity.GameRules
+}
+
+func NewAdminUseCase(
+	repository IAdminRepository,
+	sessionRepository ISessionRepository,
+	dailyLocation *time.Location,
+	dailyRules entity.GameRules,
+) *UseCase {
+	return &UseCase{
+		repository:        repository,
+		sessionRepository: sessionRepository,
+		dailyLocation:     dailyLocation,
+		dailyRules:        dailyRules,
+	}
+}
+
+// Ban locks the user out and ends all of their sessions.
+func (p *UseCase) Ban(actor *entity.User, userId int64, reason string) (*entity.User, error) {
+	user, err := p.findManagedUser(actor, userId)
+	if err != nil {
+		return nil, err
+	}
+	if err = user.Ban(reason); err != nil {
+		return nil, err
+	}
+
+	entry := entity.NewAuditEntry(actor, ActionBanUser, entity.AuditTargetUser, userTarget(user), map[string]any{
+		"reason": reason,
+	})
+	if err = p.repository.UpdateBan(user, entry); err != nil {
+		return nil, err
+	}
+	if err = p.sessionRepository.RevokeUserSessions(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (p *UseCase) Unban(actor *entity.User, userId int64) (*entity.User, error) {
+	user, err := p.findManagedUser(actor, userId)
+	if err != nil {
+		return nil, err
+	}
+	reason := user.BanReason
+	if err = user.Unban(); err != nil {
+		return nil, err
+	}
+
+	entry := entity.NewAuditEntry(actor, ActionUnbanUser, entity.AuditTargetUser, userTarget(user), map[string]any{
+		"ban_reason": reason,
+	})
+	if err = p.repository.UpdateBan(user, entry); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ResetStats clears the statistics and leaderboard results of the user.
+func (p *UseCase) ResetStats(actor *entity.User, userId int64) (*entity.User, error) {
+	user, err := p.findManagedUser(actor, userId)
+	if err != nil {
+		return nil, err
+	}
+	user.ResetStats()
+
+	entry := entity.NewAuditEntry(actor, ActionResetStats, entity.AuditTargetUser, userTarget(user), nil)
+	if err = p.repository.ResetStats(user, entry); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (p *UseCase) SetRole(actor *entity.User, userId int64, role string) (*entity.User, error) {
+	user, err := p.findManagedUser(actor, userId)
+	if err != nil {
+		return nil, err
+	}
+	previousRole := user.Role
+	if err = user.SetRole(role); err != nil {
+		return nil, err
+	}
+
+	entry := entity.NewAuditEntry(actor, ActionSetRole, entity.AuditTargetUser, userTarget(user), map[string]any{
+		"from": previousRole,
+		"to":   role,
+	})
+	if err = p.repository.UpdateRole(user, entry); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// AddWord adds the word to the dictionary as an accepted guess, or as an answer too.
+func (p *UseCase) AddWord(actor *entity.User, raw string, isAnswer bool) (*entity.Word, error) {
+	normalized, err := entity.NormalizeWord(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	word := entity.NewWord(normalized)
+	if isAnswer {
+		if err = word.Promote(); err != nil {
+			return nil, err
+		}
+	}
+
+	entry := entity.NewAuditEntry(actor, ActionAddWord, entity.AuditTargetWord, word.Word, map[string]any{
+		"is_answer": isAnswer,
+	})
+	if err = p.repository.AddWord(word, entry); err != nil {
+		if errors.Is(err, repo.ErrWordUniqConstraint) {
+			return nil, ErrWordAlreadyExists
+		}
+		return nil, err
+	}
+
+	return word, nil
+}
+
+// DisableWord neither accepts the word as a guess nor picks it as an answer any longer.
+func (p *UseCase) DisableWord(actor *entity.User, raw string) (*entity.Word, error) {
+	return p.updateWord(actor, raw, ActionDisableWord, func(word *entity.Word) error {
+		word.Disable()
+		return nil
+	})
+}
+
+// MarkAnswer makes the word eligible to be an answer.
+func (p *UseCase) MarkAnswer(actor *entity.User, raw string) (*entity.Word, error) {
+	return p.updateWord(actor, raw, ActionMarkAnswer, (*entity.Word).Promote)
+}
+
+// FindDailyPuzzles lists the puzzles scheduled from today on.
+func (p *UseCase) FindDailyPuzzles(limit int) ([]*entity.DailyPuzzle, error) {
+	return p.repository.FindDailyPuzzles(p.Today(), limit)
+}
+
+// ScheduleDailyPuzzle sets the answer of the daily puzzle of the date, unless somebody has already played it.
+func (p *UseCase) ScheduleDailyPuzzle(actor *entity.User, date time.Time, raw string) (*entity.DailyPuzzle, error) {
+	if date.Before(p.Today()) {
+		return nil, ErrDailyPuzzleInPast
+	}
+
+	normalized, err := entity.NormalizeWord(raw)
+	if err != nil {
+		return nil, err
+	}
+	word, err := p.findWord(normalized)
+	if err != nil {
+		return nil, err
+	}
+	if !word.IsAnswer || !word.IsEnabled || word.IsOffensive {
+		return nil, ErrWordNotPlayable
+	}
+	if word.Length != p.dailyRules.WordLength {
+		return nil, ErrDailyPuzzleWrongWordLength
+	}
+
+	entry := entity.NewAuditEntry(actor, ActionSchedule, entity.AuditTargetDailyPuzzle, date.Format(time.DateOnly), map[string]any{
+		"word": word.Word,
+	})
+
+	puzzle, err := p.repository.FindDailyPuzzle(date)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		puzzle = entity.NewDailyPuzzle(date, word)
+		if err = p.repository.CreateDailyPuzzle(puzzle, entry); err != nil {
+			if errors.Is(err, repo.ErrDailyPuzzleDateUniqConstraint) {
+				return nil, ErrDailyPuzzleAlreadyExists
+			}
+			return nil, err
+		}
+		puzzle.Word = word
+
+		return puzzle, nil
+	}
+
+	if err = p.ensureNotPlayed(puzzle); err != nil {
+		return nil, err
+	}
+	entry.Details["previous_word"] = puzzle.Word.Word
+	puzzle.WordId = word.Id
+	puzzle.Word = word
+	if err = p.updateDailyPuzzles([]*entity.DailyPuzzle{puzzle}, entry); err != nil {
+		return nil, err
+	}
+
+	return puzzle, nil
+}
+
+// ReorderDailyPuzzles swaps the answers of the daily puzzles of two dates.
+func (p *UseCase) ReorderDailyPuzzles(actor *entity.User, date, otherDate time.Time) ([]*entity.DailyPuzzle, error) {
+	if date.Equal(otherDate) {
+		return nil, ErrDailyPuzzleSameDates
+	}
+
+	puzzles := make([]*entity.DailyPuzzle, 0, 2)
+	for _, d := range []time.Time{date, otherDate} {
+		if d.Before(p.Today()) {
+			return nil, ErrDailyPuzzleInPast
+		}
+		puzzle, err := p.repository.FindDailyPuzzle(d)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, ErrDailyPuzzleNotFound
+			}
+			return nil, err
+		}
+		if err = p.ensureNotPlayed(puzzle); err != nil {
+			return nil, err
+		}
+		puzzles = append(puzzles, puzzle)
+	}
+
+	first, second := puzzles[0], puzzles[1]
+	first.WordId, second.WordId = second.WordId, first.WordId
+	first.Word, second.Word = second.Word, first.Word
+
+	entry := entity.NewAuditEntry(actor, ActionReorder, entity.AuditTargetDailyPuzzle, date.Format(time.DateOnly), map[string]any{
+		"swapped_with": otherDate.Format(time.DateOnly),
+	})
+	if err := p.updateDailyPuzzles(puzzles, entry); err != nil {
+		return nil, err
+	}
+
+	return puzzles, nil
+}
+
+// FindAuditEntries pages the audit trail from the latest entries back.
+func (p *UseCase) FindAuditEntries(beforeId int64, limit int) ([]*entity.AuditEntry, error) {
+	return p.repository.FindAuditEntries(beforeId, limit)
+}
+
+// Today returns the current date in the timezone daily puzzles are scheduled in.
+func (p *UseCase) Today() time.Time {
+	now := time.Now().In(p.dailyLocation)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, p.dailyLocation)
+}
+
+// ParseDate parses a YYYY-MM-DD date in the timezone daily puzzles are scheduled in.
+func (p *UseCase) ParseDate(date string) (time.Time, error) {
+	return time.ParseInLocation(time.DateOnly, date, p.dailyLocation)
+}
+
+// findManagedUser returns the user if the actor outranks them.
+func (p *UseCase) findManagedUser(actor *entity.User, userId int64) (*entity.User, error) {
+	user, err := p.repository.FindUser(userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if user.HasRole(actor.Role) {
+		return nil, ErrInsufficientRole
+	}
+
+	return user, nil
+}
+
+func (p *UseCase) findWord(normalized string) (*entity.Word, error) {
+	word, err := p.repository.FindWord(normalized)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWordNotFound
+		}
+		return nil, err
+	}
+
+	return word, nil
+}
+
+func (p *UseCase) updateWord(
+	actor *entity.User,
+	raw string,
+	action string,
+	change func(word *entity.Word) error,
+) (*entity.Word, error) {
+	normalized, err := entity.NormalizeWord(raw)
+	if err != nil {
+		return nil, err
+	}
+	word, err := p.findWord(normalized)
+	if err != nil {
+		return nil, err
+	}
+	if err = change(word); err != nil {
+		return nil, err
+	}
+
+	entry := entity.NewAuditEntry(actor, action, entity.AuditTargetWord, word.Word, nil)
+	if err = p.repository.UpdateWord(word, entry); err != nil {
+		return nil, err
+	}
+
+	return word, nil
+}
+
+func (p *UseCase) ensureNotPlayed(puzzle *entity.DailyPuzzle) error {
+	isPlayed, err := p.repository.IsDailyPuzzlePlayed(puzzle)
+	if err != nil {
+		return err
+	}
+	if isPlayed {
+		return ErrDailyPuzzleAlreadyPlayed
+	}
+
+	return nil
+}
+
+// updateDailyPuzzles saves the puzzles unless one of them has been started since ensureNotPlayed checked it.
+func (p *UseCase) updateDailyPuzzles(puzzles []*entity.DailyPuzzle, entry *entity.AuditEntry) error {
+	err := p.repository.UpdateDailyPuzzles(puzzles, entry)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrDailyPuzzleAlreadyPlayed
+	}
+
+	return err
+}
+
+func userTarget(user *entity.User) string {
+	return strconv.FormatInt(user.Id, 10)
+}
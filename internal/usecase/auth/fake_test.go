@@ -0,0 +1,337 @@
+package auth
+
+import (
+	"database/sql"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/infra/mailer"
+	"github.com/Markard/wordka/internal/repo"
+	"sync"
+	"time"
+)
+
+// fakeAuthRepository keeps the users in memory, by their email.
+type fakeAuthRepository struct {
+	mu     sync.Mutex
+	nextId int64
+	users  map[string]*entity.User
+}
+
+func newFakeAuthRepository(users ...*entity.User) *fakeAuthRepository {
+	r := &fakeAuthRepository{users: make(map[string]*entity.User)}
+	for _, user := range users {
+		_ = r.Create(user)
+	}
+	return r
+}
+
+func (r *fakeAuthRepository) Create(user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[user.Email]; ok {
+		return repo.ErrEmailUniqConstraint
+	}
+	r.nextId++
+	user.Id = r.nextId
+	r.users[user.Email] = user
+	return nil
+}
+
+func (r *fakeAuthRepository) FindBy(email string) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[email]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeAuthRepository) FindById(id int64) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if user.Id == id {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeAuthRepository) UpdatePassword(user *entity.User, previousPassword string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := r.find(user.Id)
+	if saved == nil || saved.Password != previousPassword {
+		return sql.ErrNoRows
+	}
+	saved.Password = user.Password
+	if !saved.IsEmailVerified() {
+		saved.EmailVerifiedAt = user.EmailVerifiedAt
+	}
+	saved.FailedLogins = user.FailedLogins
+	saved.LockedUntil = user.LockedUntil
+	saved.UpdatedAt = user.UpdatedAt
+	return nil
+}
+
+func (r *fakeAuthRepository) UpdateLoginFailures(user *entity.User) error {
+	return r.update(user, func(saved *entity.User) {
+		saved.FailedLogins = user.FailedLogins
+		saved.LockedUntil = user.LockedUntil
+	})
+}
+
+func (r *fakeAuthRepository) RecordFailedLogin(user *entity.User) error {
+	return r.update(user, func(saved *entity.User) {
+		saved.FailedLogins++
+		user.FailedLogins = saved.FailedLogins
+	})
+}
+
+func (r *fakeAuthRepository) LockOut(user *entity.User) error {
+	return r.update(user, func(saved *entity.User) {
+		if user.LockedUntil.After(saved.LockedUntil.Time) {
+			saved.LockedUntil = user.LockedUntil
+		}
+	})
+}
+
+func (r *fakeAuthRepository) ClaimVerificationResend(user *entity.User, period time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := r.find(user.Id)
+	if saved == nil || saved.VerificationResendIn(period) > 0 {
+		return sql.ErrNoRows
+	}
+	saved.MarkVerificationSent()
+	user.EmailVerificationSentAt = saved.EmailVerificationSentAt
+	return nil
+}
+
+func (r *fakeAuthRepository) UpdateVerificationSent(user *entity.User) error {
+	return r.update(user, func(saved *entity.User) {
+		saved.EmailVerificationSentAt = user.EmailVerificationSentAt
+	})
+}
+
+func (r *fakeAuthRepository) UpdateEmailVerified(user *entity.User) error {
+	return r.update(user, func(saved *entity.User) {
+		saved.EmailVerifiedAt = user.EmailVerifiedAt
+		saved.UpdatedAt = user.UpdatedAt
+	})
+}
+
+func (r *fakeAuthRepository) user(email string) *entity.User {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.users[email]
+}
+
+func (r *fakeAuthRepository) update(user *entity.User, apply func(saved *entity.User)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := r.find(user.Id)
+	if saved == nil {
+		return sql.ErrNoRows
+	}
+	apply(saved)
+	return nil
+}
+
+func (r *fakeAuthRepository) find(id int64) *entity.User {
+	for _, user := range r.users {
+		if user.Id == id {
+			return user
+		}
+	}
+	return nil
+}
+
+// fakeSessionRepository keeps the sessions in memory, the refresh tokens are not looked up by the tests.
+type fakeSessionRepository struct {
+	mu       sync.Mutex
+	sessions map[string]*entity.Session
+}
+
+func newFakeSessionRepository() *fakeSessionRepository {
+	return &fakeSessionRepository{sessions: make(map[string]*entity.Session)}
+}
+
+func (r *fakeSessionRepository) CreateSession(session *entity.Session, _ *entity.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[session.Id] = session
+	return nil
+}
+
+func (r *fakeSessionRepository) CreateRefreshToken(*entity.RefreshToken) error {
+	return nil
+}
+
+func (r *fakeSessionRepository) UseRefreshToken(string) (*entity.RefreshToken, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeSessionRepository) FindRefreshToken(string) (*entity.RefreshToken, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeSessionRepository) FindSession(id string) (*entity.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return session, nil
+}
+
+func (r *fakeSessionRepository) FindSessionByAccessJti(string) (*entity.Session, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeSessionRepository) RevokeSession(session *entity.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session.RevokedAt.Time = time.Now()
+	return nil
+}
+
+func (r *fakeSessionRepository) RevokeUserSessions(user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, session := range r.sessions {
+		if session.UserId == user.Id && !session.IsRevoked() {
+			session.RevokedAt.Time = time.Now()
+		}
+	}
+	return nil
+}
+
+func (r *fakeSessionRepository) RevokeAccessToken(string, time.Time) error {
+	return nil
+}
+
+func (r *fakeSessionRepository) activeSessions(user *entity.User) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	active := 0
+	for _, session := range r.sessions {
+		if session.UserId == user.Id && !session.IsRevoked() {
+			active++
+		}
+	}
+	return active
+}
+
+// fakeIdentityRepository keeps the external logins and the identities in memory,
+// the users are saved to the auth repository.
+type fakeIdentityRepository struct {
+	mu         sync.Mutex
+	users      *fakeAuthRepository
+	logins     map[string]*entity.ExternalLogin
+	identities []*entity.UserIdentity
+}
+
+func newFakeIdentityRepository(users *fakeAuthRepository) *fakeIdentityRepository {
+	return &fakeIdentityRepository{users: users, logins: make(map[string]*entity.ExternalLogin)}
+}
+
+func (r *fakeIdentityRepository) CreateExternalLogin(login *entity.ExternalLogin) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logins[login.StateHash] = login
+	return nil
+}
+
+func (r *fakeIdentityRepository) UseExternalLogin(stateHash string) (*entity.ExternalLogin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	login, ok := r.logins[stateHash]
+	if !ok || !login.UsedAt.IsZero() {
+		return nil, sql.ErrNoRows
+	}
+	login.UsedAt.Time = time.Now()
+	return login, nil
+}
+
+func (r *fakeIdentityRepository) FindIdentity(provider string, subject string) (*entity.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			user, err := r.users.FindById(identity.UserId)
+			if err != nil {
+				return nil, err
+			}
+			identity.User = user
+			return identity, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeIdentityRepository) UpdateIdentityLogin(*entity.UserIdentity) error {
+	return nil
+}
+
+func (r *fakeIdentityRepository) CreateUserWithIdentity(user *entity.User, identity *entity.UserIdentity) error {
+	if err := r.users.Create(user); err != nil {
+		return err
+	}
+	return r.insert(user, identity)
+}
+
+func (r *fakeIdentityRepository) LinkIdentity(user *entity.User, identity *entity.UserIdentity) error {
+	err := r.users.update(user, func(saved *entity.User) {
+		saved.Password = user.Password
+		saved.EmailVerifiedAt = user.EmailVerifiedAt
+		saved.UpdatedAt = user.UpdatedAt
+	})
+	if err != nil {
+		return err
+	}
+	return r.insert(user, identity)
+}
+
+func (r *fakeIdentityRepository) insert(user *entity.User, identity *entity.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, linked := range r.identities {
+		if linked.Provider == identity.Provider && linked.Subject == identity.Subject {
+			return repo.ErrIdentityUniqConstraint
+		}
+	}
+	identity.UserId = user.Id
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *fakeIdentityRepository) expireLogins() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, login := range r.logins {
+		login.ExpiresAt = time.Now().Add(-time.Second)
+	}
+}
+
+// fakeMailer keeps the messages sent.
+type fakeMailer struct {
+	mu       sync.Mutex
+	messages []*mailer.Message
+}
+
+func (m *fakeMailer) Send(message *mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+func (m *fakeMailer) sent() []*mailer.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.messages
+}
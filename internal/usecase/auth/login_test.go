@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"errors"
+	"github.com/Markard/wordka/internal/entity"
+	"testing"
+	"time"
+)
+
+const password = "correct horse battery"
+
+func TestLoginDoesNotRevealRegisteredEmails(t *testing.T) {
+	lockout := entity.LoginLockout{Threshold: 2, Duration: time.Hour, MaxDuration: 24 * time.Hour}
+	registered := newUser(t, "player@example.com")
+	locked := newUser(t, "locked@example.com")
+	repository := newFakeAuthRepository(registered, locked)
+	auth := NewAuth(repository, nil, nil, nil, nil, nil, Options{Lockout: lockout})
+
+	for range lockout.Threshold {
+		if _, err := auth.Login(locked.Email, "wrong password"); !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("Login() with a wrong password error = %v, want %v", err, ErrUserNotFound)
+		}
+	}
+	if repository.user(locked.Email).LockedFor() == 0 {
+		t.Fatal("the account is not locked out after the failed logins")
+	}
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+	}{
+		{name: "unknown email", email: "nobody@example.com", password: password},
+		{name: "wrong password", email: registered.Email, password: "wrong password"},
+		{name: "locked account with a wrong password", email: locked.Email, password: "wrong password"},
+		{name: "locked account with the password", email: locked.Email, password: password},
+	}
+
+	comparisons := countComparisons(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*comparisons = 0
+			if _, err := auth.Login(tt.email, tt.password); !errors.Is(err, ErrUserNotFound) {
+				t.Errorf("Login() error = %v, want %v", err, ErrUserNotFound)
+			}
+			if *comparisons != 1 {
+				t.Errorf("Login() compared %d password hashes, want 1 on every path", *comparisons)
+			}
+		})
+	}
+}
+
+func TestLoginCountsFailedLogins(t *testing.T) {
+	user := newUser(t, "player@example.com")
+	repository := newFakeAuthRepository(user)
+	lockout := entity.LoginLockout{Threshold: 3, Duration: time.Minute, MaxDuration: time.Hour}
+	auth := NewAuth(repository, nil, nil, nil, nil, nil, Options{Lockout: lockout})
+
+	for i := 1; i <= lockout.Threshold; i++ {
+		_, _ = auth.Login(user.Email, "wrong password")
+		saved := repository.user(user.Email)
+		if saved.FailedLogins != i {
+			t.Fatalf("failed logins = %d, want %d", saved.FailedLogins, i)
+		}
+		if isLocked := saved.LockedFor() > 0; isLocked != (i == lockout.Threshold) {
+			t.Errorf("after %d failed logins locked = %t", i, isLocked)
+		}
+	}
+}
+
+func newUser(t *testing.T, email string) *entity.User {
+	t.Helper()
+	user, err := entity.NewUser("Игрок", email, password)
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	return user
+}
+
+// countComparisons counts the password hash comparisons until the test ends. The entity tests make sure
+// every hash compared is of the same cost, so the same count means the same work.
+func countComparisons(t *testing.T) *int {
+	t.Helper()
+	count := 0
+	compare := entity.ComparePassword
+	entity.ComparePassword = func(hash, password []byte) error {
+		count++
+		return compare(hash, password)
+	}
+	t.Cleanup(func() { entity.ComparePassword = compare })
+
+	return &count
+}
@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/repo"
+	"github.com/Markard/wordka/pkg/oidc"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	minNameLength     = 3
+	maxNameLength     = 255
+	defaultPlayerName = "Игрок"
+)
+
+var (
+	ErrUnknownProvider          = errors.New("unknown login provider")
+	ErrInvalidLoginState        = errors.New("external login is invalid or expired")
+	ErrExternalLoginFailed      = errors.New("the provider did not confirm the login")
+	ErrNoProviderEmail          = errors.New("the provider did not share the email")
+	ErrProviderEmailNotVerified = errors.New("the email is registered already and the provider has not verified it")
+)
+
+type IIdentityRepository interface {
+	CreateExternalLogin(login *entity.ExternalLogin) error
+	UseExternalLogin(stateHash string) (*entity.ExternalLogin, error)
+	FindIdentity(provider string, subject string) (*entity.UserIdentity, error)
+	UpdateIdentityLogin(identity *entity.UserIdentity) error
+	CreateUserWithIdentity(user *entity.User, identity *entity.UserIdentity) error
+	LinkIdentity(user *entity.User, identity *entity.UserIdentity) error
+}
+
+// IExternalProvider is an OpenID Connect provider the users can log in with, see oidc.Provider.
+type IExternalProvider interface {
+	AuthCodeUrl(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*oidc.Claims, error)
+}
+
+// StartExternalLogin returns the page of the provider to send the user to and the state of the login.
+// The provider sends them back with a code and the state, which complete the login, see CompleteExternalLogin.
+// The state has to be kept by the browser starting the login as well, e.g. in a cookie.
+func (auth *UseCase) StartExternalLogin(ctx context.Context, providerName string) (string, string, error) {
+	provider, ok := auth.providers[providerName]
+	if !ok {
+		return "", "", ErrUnknownProvider
+	}
+
+	codeVerifier := oidc.NewCodeVerifier()
+	nonce := oidc.NewNonce()
+	login, state := entity.NewExternalLogin(providerName, codeVerifier, nonce, auth.options.ExternalLoginTtl)
+	authUrl, err := provider.AuthCodeUrl(ctx, state, nonce, oidc.CodeChallenge(codeVerifier))
+	if err != nil {
+		return "", "", err
+	}
+	if err = auth.identityRepository.CreateExternalLogin(login); err != nil {
+		return "", "", err
+	}
+
+	return authUrl, state, nil
+}
+
+// CompleteExternalLogin exchanges the code for the identity of the user at the provider and starts a session.
+// The identity is linked to the user it was linked to before, otherwise to the user registered with the email,
+// if the provider has verified it, otherwise a new user without a password is registered.
+// The browserState is the state kept by the browser completing the login: only the browser which started
+// the login completes it, so nobody can log a victim in to their own account by sending them a link.
+func (auth *UseCase) CompleteExternalLogin(
+	ctx context.Context,
+	providerName, code, state, browserState string,
+) (*Tokens, error) {
+	provider, ok := auth.providers[providerName]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	if subtle.ConstantTimeCompare([]byte(state), []byte(browserState)) != 1 {
+		return nil, ErrInvalidLoginState
+	}
+
+	login, err := auth.identityRepository.UseExternalLogin(entity.HashLoginState(state))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidLoginState
+		}
+		return nil, err
+	}
+	if login.IsExpired() || login.Provider != providerName {
+		return nil, ErrInvalidLoginState
+	}
+
+	claims, err := provider.Exchange(ctx, code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExternalLoginFailed, err)
+	}
+
+	user, err := auth.externalUser(providerName, claims)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsBanned() {
+		return nil, ErrUserBanned
+	}
+
+	return auth.startSession(user)
+}
+
+func (auth *UseCase) externalUser(providerName string, claims *oidc.Claims) (*entity.User, error) {
+	identity, err := auth.identityRepository.FindIdentity(providerName, claims.Subject)
+	if err == nil {
+		identity.Email = claims.Email
+		identity.MarkLogin()
+		if err = auth.identityRepository.UpdateIdentityLogin(identity); err != nil {
+			return nil, err
+		}
+		return identity.User, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	if claims.Email == "" {
+		return nil, ErrNoProviderEmail
+	}
+	user, err := auth.repository.FindBy(claims.Email)
+	if err == nil {
+		return auth.linkIdentity(user, providerName, claims)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	user = entity.NewExternalUser(externalUserName(claims), claims.Email, claims.EmailVerified)
+	identity = entity.NewUserIdentity(user, providerName, claims.Subject, claims.Email)
+	if err = auth.identityRepository.CreateUserWithIdentity(user, identity); err != nil {
+		if errors.Is(err, repo.ErrEmailUniqConstraint) {
+			return nil, ErrUserAlreadyExists
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// linkIdentity links the identity to the user registered with the same email. Only an email verified
+// by the provider proves it is the same person. If the user has never verified the email here, the account
+// may have been registered by somebody else waiting for the owner to link it, so the password is removed
+// and the sessions are ended: from now on the account belongs to whoever owns the email.
+func (auth *UseCase) linkIdentity(user *entity.User, providerName string, claims *oidc.Claims) (*entity.User, error) {
+	if !claims.EmailVerified {
+		return nil, ErrProviderEmailNotVerified
+	}
+
+	isClaimed := !user.IsEmailVerified()
+	if isClaimed {
+		user.RemovePassword()
+		user.VerifyEmail()
+	}
+
+	identity := entity.NewUserIdentity(user, providerName, claims.Subject, claims.Email)
+	if err := auth.identityRepository.LinkIdentity(user, identity); err != nil {
+		return nil, err
+	}
+	if isClaimed {
+		if err := auth.sessionRepository.RevokeUserSessions(user); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// externalUserName is the name from the provider if it fits the rules of the registration,
+// otherwise the name part of the email or a placeholder.
+func externalUserName(claims *oidc.Claims) string {
+	for _, name := range []string{claims.Name, strings.Split(claims.Email, "@")[0]} {
+		name = strings.TrimSpace(name)
+		if utf8.RuneCountInString(name) < minNameLength {
+			continue
+		}
+		if utf8.RuneCountInString(name) > maxNameLength {
+			name = string([]rune(name)[:maxNameLength])
+		}
+		return name
+	}
+
+	return defaultPlayerName
+}
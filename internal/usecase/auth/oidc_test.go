@@ -0,0 +1,259 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"github.com/Markard/wordka/internal/entity"
+	serviceJwt "github.com/Markard/wordka/internal/infra/service/jwt"
+	"github.com/Markard/wordka/pkg/oidc"
+	"github.com/Markard/wordka/pkg/oidc/mock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const providerName = "mock"
+
+type externalLoginTest struct {
+	auth       *UseCase
+	users      *fakeAuthRepository
+	sessions   *fakeSessionRepository
+	identities *fakeIdentityRepository
+	provider   *mock.Server
+}
+
+func TestExternalLoginRegistersUser(t *testing.T) {
+	test := newExternalLoginTest(t, mock.User{Subject: "42", Email: "player@example.com", EmailVerified: true, Name: "Игрок"})
+
+	code, state := test.authorize(t)
+	tokens, err := test.auth.CompleteExternalLogin(context.Background(), providerName, code, state, state)
+	if err != nil {
+		t.Fatalf("CompleteExternalLogin() error = %v", err)
+	}
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Errorf("CompleteExternalLogin() = %+v, want the tokens", tokens)
+	}
+
+	user := test.users.user("player@example.com")
+	if user == nil {
+		t.Fatal("the user is not registered")
+	}
+	if user.Name != "Игрок" || user.HasPassword() || !user.IsEmailVerified() {
+		t.Errorf("registered user = %+v, want a verified user without a password", user)
+	}
+
+	// The identity is found by the subject from then on, even when the email at the provider changes.
+	test.provider.User.Email = "renamed@example.com"
+	code, state = test.authorize(t)
+	if _, err = test.auth.CompleteExternalLogin(context.Background(), providerName, code, state, state); err != nil {
+		t.Fatalf("CompleteExternalLogin() of a linked identity error = %v", err)
+	}
+	if test.users.user("renamed@example.com") != nil {
+		t.Error("a linked identity registered another user")
+	}
+	if sessions := test.sessions.activeSessions(user); sessions != 2 {
+		t.Errorf("active sessions = %d, want 2", sessions)
+	}
+}
+
+func TestExternalLoginRejectsInvalidState(t *testing.T) {
+	tests := []struct {
+		name     string
+		complete func(t *testing.T, test *externalLoginTest, code, state string) error
+	}{
+		{
+			name: "reused state",
+			complete: func(t *testing.T, test *externalLoginTest, code, state string) error {
+				if _, err := test.auth.CompleteExternalLogin(context.Background(), providerName, code, state, state); err != nil {
+					t.Fatalf("first CompleteExternalLogin() error = %v", err)
+				}
+				_, err := test.auth.CompleteExternalLogin(context.Background(), providerName, code, state, state)
+				return err
+			},
+		},
+		{
+			name: "expired state",
+			complete: func(t *testing.T, test *externalLoginTest, code, state string) error {
+				test.identities.expireLogins()
+				_, err := test.auth.CompleteExternalLogin(context.Background(), providerName, code, state, state)
+				return err
+			},
+		},
+		{
+			name: "unknown state",
+			complete: func(t *testing.T, test *externalLoginTest, code, state string) error {
+				_, err := test.auth.CompleteExternalLogin(context.Background(), providerName, code, "forged", "forged")
+				return err
+			},
+		},
+		{
+			name: "state of another browser",
+			complete: func(t *testing.T, test *externalLoginTest, code, state string) error {
+				_, err := test.auth.CompleteExternalLogin(context.Background(), providerName, code, state, "")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := newExternalLoginTest(t, mock.User{Subject: "42", Email: "player@example.com", EmailVerified: true})
+			code, state := test.authorize(t)
+
+			if err := tt.complete(t, test, code, state); !errors.Is(err, ErrInvalidLoginState) {
+				t.Errorf("CompleteExternalLogin() error = %v, want %v", err, ErrInvalidLoginState)
+			}
+		})
+	}
+}
+
+func TestExternalLoginLinksVerifiedAccount(t *testing.T) {
+	test := newExternalLoginTest(t, mock.User{Subject: "42", Email: "player@example.com", EmailVerified: true})
+	registered := newUser(t, "player@example.com")
+	registered.VerifyEmail()
+	test.register(t, registered)
+
+	code, state := test.authorize(t)
+	if _, err := test.auth.CompleteExternalLogin(context.Background(), providerName, code, state, state); err != nil {
+		t.Fatalf("CompleteExternalLogin() error = %v", err)
+	}
+
+	user := test.users.user(registered.Email)
+	if user.Id != registered.Id || !user.IsPasswordMatch(password) {
+		t.Errorf("linked user = %+v, want the registered user with the password kept", user)
+	}
+	if identity, err := test.identities.FindIdentity(providerName, "42"); err != nil || identity.UserId != registered.Id {
+		t.Errorf("FindIdentity() = %+v, %v, want the identity of the registered user", identity, err)
+	}
+	if sessions := test.sessions.activeSessions(user); sessions != 2 {
+		t.Errorf("active sessions = %d, want the one from before and the new one", sessions)
+	}
+}
+
+func TestExternalLoginClaimsUnverifiedAccount(t *testing.T) {
+	test := newExternalLoginTest(t, mock.User{Subject: "42", Email: "player@example.com", EmailVerified: true})
+	registered := newUser(t, "player@example.com")
+	test.register(t, registered)
+
+	code, state := test.authorize(t)
+	if _, err := test.auth.CompleteExternalLogin(context.Background(), providerName, code, state, state); err != nil {
+		t.Fatalf("CompleteExternalLogin() error = %v", err)
+	}
+
+	user := test.users.user(registered.Email)
+	if user.Id != registered.Id {
+		t.Fatalf("claimed user id = %d, want %d", user.Id, registered.Id)
+	}
+	if user.HasPassword() || user.IsPasswordMatch(password) {
+		t.Error("the password of the claimed account is kept")
+	}
+	if !user.IsEmailVerified() {
+		t.Error("the email of the claimed account is not verified")
+	}
+	if sessions := test.sessions.activeSessions(user); sessions != 1 {
+		t.Errorf("active sessions = %d, want only the new one", sessions)
+	}
+}
+
+func TestExternalLoginRefusesUnverifiedProviderEmail(t *testing.T) {
+	test := newExternalLoginTest(t, mock.User{Subject: "42", Email: "player@example.com", EmailVerified: false})
+	registered := newUser(t, "player@example.com")
+	test.register(t, registered)
+
+	code, state := test.authorize(t)
+	_, err := test.auth.CompleteExternalLogin(context.Background(), providerName, code, state, state)
+	if !errors.Is(err, ErrProviderEmailNotVerified) {
+		t.Fatalf("CompleteExternalLogin() error = %v, want %v", err, ErrProviderEmailNotVerified)
+	}
+
+	user := test.users.user(registered.Email)
+	if !user.IsPasswordMatch(password) || user.IsEmailVerified() {
+		t.Errorf("user = %+v, want it unchanged", user)
+	}
+	if _, err = test.identities.FindIdentity(providerName, "42"); err == nil {
+		t.Error("the identity is linked")
+	}
+	if sessions := test.sessions.activeSessions(user); sessions != 1 {
+		t.Errorf("active sessions = %d, want the one from before", sessions)
+	}
+}
+
+func newExternalLoginTest(t *testing.T, user mock.User) *externalLoginTest {
+	t.Helper()
+	server, err := mock.NewServer("", user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	server.Issuer = httpServer.URL
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := serviceJwt.NewKeySet(&serviceJwt.Key{Id: "test", PrivateKey: privateKey, PublicKey: &privateKey.PublicKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := newFakeAuthRepository()
+	sessions := newFakeSessionRepository()
+	identities := newFakeIdentityRepository(users)
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:      httpServer.URL,
+		ClientId:    "wordka",
+		RedirectUrl: "https://wordka.test/oauth/mock/callback",
+	}, nil)
+	auth := NewAuth(
+		users,
+		sessions,
+		identities,
+		serviceJwt.NewService(keys, serviceJwt.Options{Issuer: "wordka", Audience: "wordka"}),
+		nil,
+		map[string]IExternalProvider{providerName: provider},
+		Options{AccessTokenTtl: time.Minute, RefreshTokenTtl: time.Hour, ExternalLoginTtl: time.Minute},
+	)
+
+	return &externalLoginTest{auth: auth, users: users, sessions: sessions, identities: identities, provider: server}
+}
+
+// register saves the user with a session, as if they had logged in with the password before.
+func (test *externalLoginTest) register(t *testing.T, user *entity.User) {
+	t.Helper()
+	if err := test.users.Create(user); err != nil {
+		t.Fatal(err)
+	}
+	_ = test.sessions.CreateSession(entity.NewSession(user), nil)
+}
+
+// authorize starts the login and goes through it at the provider, returning the code and the state
+// the provider redirects back with.
+func (test *externalLoginTest) authorize(t *testing.T) (string, string) {
+	t.Helper()
+	authUrl, state, err := test.auth.StartExternalLogin(context.Background(), providerName)
+	if err != nil {
+		t.Fatalf("StartExternalLogin() error = %v", err)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorization answered %s, want a redirect", resp.Status)
+	}
+	if got := location.Query().Get("state"); got != state {
+		t.Fatalf("state = %s, want %s", got, state)
+	}
+
+	return location.Query().Get("code"), state
+}
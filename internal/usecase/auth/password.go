@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/infra/mailer"
+	serviceJwt "github.com/Markard/wordka/internal/infra/service/jwt"
+	"github.com/Markard/wordka/pkg/slogext"
+	"log/slog"
+	"net/url"
+	"time"
+)
+
+var (
+	ErrInvalidResetToken = errors.New("password reset token is invalid or expired")
+	ErrWrongPassword     = errors.New("current password is incorrect")
+)
+
+// ForgotPassword emails a password reset link. Unknown emails are silently ignored,
+// so that the outcome does not reveal who is registered. The email is sent in the background,
+// otherwise the time the mail server takes would tell the registered emails apart.
+func (auth *UseCase) ForgotPassword(email string) error {
+	user, err := auth.repository.FindBy(email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	go func() {
+		if err := auth.sendPasswordReset(user); err != nil {
+			slogext.Error(slog.Default(), fmt.Errorf("password reset email: %w", err))
+		}
+	}()
+
+	return nil
+}
+
+func (auth *UseCase) sendPasswordReset(user *entity.User) error {
+	token := serviceJwt.NewActionToken(
+		serviceJwt.ActionResetPassword,
+		user.Id,
+		user.Email,
+		user.PasswordStamp(),
+		time.Now().Add(auth.options.PasswordResetTokenTtl),
+	)
+	tokenString, err := auth.jwtService.CreateActionTokenStringWithES256(token)
+	if err != nil {
+		return err
+	}
+
+	link := auth.options.PasswordResetUrl + "?token=" + url.QueryEscape(tokenString)
+	return auth.mailer.Send(&mailer.Message{
+		To:      user.Email,
+		Subject: "Восстановление пароля",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nЧтобы задать новый пароль в Вордке, перейдите по ссылке:\n%s\n\n"+
+				"Ссылка действует до %s и только один раз. "+
+				"Если вы не запрашивали восстановление пароля, просто проигнорируйте это письмо.\n",
+			user.Name,
+			link,
+			token.Exp.Format("02.01.2006 15:04 MST"),
+		),
+	})
+}
+
+// ResetPassword sets the password of the user the token was sent to and logs them out everywhere.
+// The token is bound to the old password, so it cannot be used twice, not even by concurrent requests.
+func (auth *UseCase) ResetPassword(tokenString string, rawPassword string) error {
+	token, err := auth.jwtService.VerifyActionTokenStringWithES256(tokenString, serviceJwt.ActionResetPassword)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	user, err := auth.repository.FindById(token.Sub)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+	if user.Email != token.Email || user.PasswordStamp() != token.Stamp {
+		return ErrInvalidResetToken
+	}
+
+	previousPassword := user.Password
+	if err = user.ChangePassword(rawPassword); err != nil {
+		return err
+	}
+	// Following the link has proven the ownership of the email as well.
+	if !user.IsEmailVerified() {
+		user.VerifyEmail()
+	}
+	user.ResetLoginFailures()
+	if err = auth.repository.UpdatePassword(user, previousPassword); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	return auth.sessionRepository.RevokeUserSessions(user)
+}
+
+// ChangePassword replaces the password of the user and logs them out everywhere,
+// returning the tokens of a new session for the current device. A user of external providers
+// without a password sets one without confirming it. A password changed concurrently is no longer
+// the current one, so the change is refused as with a wrong password.
+func (auth *UseCase) ChangePassword(user *entity.User, currentPassword string, rawPassword string) (*Tokens, error) {
+	if user.HasPassword() && !user.IsPasswordMatch(currentPassword) {
+		return nil, ErrWrongPassword
+	}
+
+	previousPassword := user.Password
+	if err := user.ChangePassword(rawPassword); err != nil {
+		return nil, err
+	}
+	user.ResetLoginFailures()
+	if err := auth.repository.UpdatePassword(user, previousPassword); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWrongPassword
+		}
+		return nil, err
+	}
+	if err := auth.sessionRepository.RevokeUserSessions(user); err != nil {
+		return nil, err
+	}
+
+	return auth.startSession(user)
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegisterWithTakenEmail(t *testing.T) {
+	tests := []struct {
+		name                string
+		uniformRegistration bool
+		wantNotices         int
+	}{
+		{name: "uniform registration notifies the owner", uniformRegistration: true, wantNotices: 1},
+		{name: "registration tells the email is taken", uniformRegistration: false, wantNotices: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registered := newUser(t, "player@example.com")
+			repository := newFakeAuthRepository(registered)
+			mail := &fakeMailer{}
+			auth := NewAuth(repository, nil, nil, nil, mail, nil, Options{UniformRegistration: tt.uniformRegistration})
+
+			user, err := auth.Register("Другой игрок", registered.Email, "another password")
+			if !errors.Is(err, ErrUserAlreadyExists) || user != nil {
+				t.Fatalf("Register() = %v, %v, want %v", user, err, ErrUserAlreadyExists)
+			}
+			if auth.IsRegistrationUniform() != tt.uniformRegistration {
+				t.Errorf("IsRegistrationUniform() = %t, want %t", auth.IsRegistrationUniform(), tt.uniformRegistration)
+			}
+			if saved := repository.user(registered.Email); saved.Password != registered.Password || saved.Name != registered.Name {
+				t.Error("Register() has changed the registered user")
+			}
+
+			sent := mail.sent()
+			if len(sent) != tt.wantNotices {
+				t.Fatalf("sent %d emails, want %d", len(sent), tt.wantNotices)
+			}
+			if len(sent) > 0 && sent[0].To != registered.Email {
+				t.Errorf("notice sent to %s, want %s", sent[0].To, registered.Email)
+			}
+		})
+	}
+}
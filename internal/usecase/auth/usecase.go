@@ -1,31 +1,113 @@
 package auth
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/infra/mailer"
 	serviceJwt "github.com/Markard/wordka/internal/infra/service/jwt"
 	"github.com/Markard/wordka/internal/repo"
+	"time"
 )
 
 var (
-	ErrUserNotFound      = errors.New("user with such email not found")
-	ErrUserAlreadyExists = errors.New("user with such email already exists")
+	ErrUserNotFound        = errors.New("user with such email not found")
+	ErrUserAlreadyExists   = errors.New("user with such email already exists")
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used, the session has been revoked")
+	ErrUserBanned          = errors.New("the account is banned")
+	ErrNoticeNotSent       = errors.New("already registered notice could not be sent")
 )
 
 type IAuthRepository interface {
 	Create(user *entity.User) error
 	FindBy(email string) (*entity.User, error)
+	FindById(id int64) (*entity.User, error)
+	UpdatePassword(user *entity.User, previousPassword string) error
+	UpdateLoginFailures(user *entity.User) error
+	RecordFailedLogin(user *entity.User) error
+	LockOut(user *entity.User) error
+	ClaimVerificationResend(user *entity.User, period time.Duration) error
+	UpdateVerificationSent(user *entity.User) error
+	UpdateEmailVerified(user *entity.User) error
+}
+
+type ISessionRepository interface {
+	CreateSession(session *entity.Session, refreshToken *entity.RefreshToken) error
+	CreateRefreshToken(refreshToken *entity.RefreshToken) error
+	UseRefreshToken(tokenHash string) (*entity.RefreshToken, error)
+	FindRefreshToken(tokenHash string) (*entity.RefreshToken, error)
+	FindSession(id string) (*entity.Session, error)
+	FindSessionByAccessJti(jti string) (*entity.Session, error)
+	RevokeSession(session *entity.Session) error
+	RevokeUserSessions(user *entity.User) error
+	RevokeAccessToken(jti string, expiresAt time.Time) error
+}
+
+// Tokens are issued on login and on every refresh: a short-lived access token for the API
+// and a single-use refresh token to get the next pair.
+type Tokens struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+// Options configure the lifetimes of the tokens and the links emailed to users.
+type Options struct {
+	AccessTokenTtl  time.Duration
+	RefreshTokenTtl time.Duration
+	// VerificationUrl is the page the verification link leads to, the token is added as the "token" parameter.
+	VerificationUrl          string
+	VerificationTokenTtl     time.Duration
+	VerificationResendPeriod time.Duration
+	// PasswordResetUrl is the page the password reset link leads to, with the "token" parameter as well.
+	PasswordResetUrl      string
+	PasswordResetTokenTtl time.Duration
+	// Lockout locks the accounts out after repeated failed logins.
+	Lockout entity.LoginLockout
+	// UniformRegistration keeps the registration from telling whether an email is taken:
+	// the owner of a taken email is notified by email instead.
+	UniformRegistration bool
+	// ExternalLoginTtl is how long a login at an external provider may take.
+	ExternalLoginTtl time.Duration
 }
 
 type UseCase struct {
-	repository IAuthRepository
-	jwtService *serviceJwt.Service
+	repository         IAuthRepository
+	sessionRepository  ISessionRepository
+	identityRepository IIdentityRepository
+	jwtService         *serviceJwt.Service
+	mailer             mailer.Mailer
+	providers          map[string]IExternalProvider
+	options            Options
 }
 
-func NewAuth(repository IAuthRepository, tokenService *serviceJwt.Service) *UseCase {
-	return &UseCase{repository: repository, jwtService: tokenService}
+func NewAuth(
+	repository IAuthRepository,
+	sessionRepository ISessionRepository,
+	identityRepository IIdentityRepository,
+	tokenService *serviceJwt.Service,
+	mailer mailer.Mailer,
+	providers map[string]IExternalProvider,
+	options Options,
+) *UseCase {
+	return &UseCase{
+		repository:         repository,
+		sessionRepository:  sessionRepository,
+		identityRepository: identityRepository,
+		jwtService:         tokenService,
+		mailer:             mailer,
+		providers:          providers,
+		options:            options,
+	}
 }
 
+// Register creates the user and emails a verification link. A failure to send the email
+// is reported with ErrVerificationNotSent along with the created user: it can be resent later.
+// With Options.UniformRegistration, a taken email gets a notice instead, and the caller should
+// answer the same way in both cases, see IsRegistrationUniform.
 func (auth *UseCase) Register(name string, email string, rawPassword string) (*entity.User, error) {
 	user, err := entity.NewUser(name, email, rawPassword)
 	if err != nil {
@@ -34,32 +116,226 @@ func (auth *UseCase) Register(name string, email string, rawPassword string) (*e
 	err = auth.repository.Create(user)
 	if err != nil {
 		if errors.Is(err, repo.ErrEmailUniqConstraint) {
+			if auth.options.UniformRegistration {
+				return nil, auth.notifyAlreadyRegistered(email)
+			}
 			return nil, ErrUserAlreadyExists
 		}
 		return nil, err
 	}
 
+	if err = auth.SendVerification(user); err != nil {
+		return user, fmt.Errorf("%w: %w", ErrVerificationNotSent, err)
+	}
+
 	return user, nil
 }
 
-func (auth *UseCase) Login(email string, password string) (string, error) {
+// notifyAlreadyRegistered tells the owner of the email that somebody tried to register with it.
+// It returns ErrUserAlreadyExists once the notice is sent.
+func (auth *UseCase) notifyAlreadyRegistered(email string) error {
+	user, err := auth.repository.FindBy(email)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNoticeNotSent, err)
+	}
+
+	err = auth.mailer.Send(&mailer.Message{
+		To:      user.Email,
+		Subject: "Попытка регистрации в Вордке",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nКто-то, возможно вы, пытался зарегистрироваться в Вордке с этим адресом почты, "+
+				"но у вас уже есть аккаунт. Просто войдите в него, а если не помните пароль, восстановите его "+
+				"на странице входа.\n\nЕсли это были не вы, просто проигнорируйте это письмо.\n",
+			user.Name,
+		),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNoticeNotSent, err)
+	}
+
+	return ErrUserAlreadyExists
+}
+
+// Jwks returns the public keys verifying the tokens issued by the app.
+func (auth *UseCase) Jwks() *serviceJwt.Jwks {
+	return auth.jwtService.Jwks()
+}
+
+// IsRegistrationUniform tells whether the outcome of a registration must not be revealed to the client.
+func (auth *UseCase) IsRegistrationUniform() bool {
+	return auth.options.UniformRegistration
+}
+
+// Login starts a new session of the user. Wrong passwords are counted, locking the account out
+// for a while once there are too many of them, see entity.LoginLockout. Unknown emails, wrong passwords
+// and locked accounts all fail with ErrUserNotFound after checking a password, so neither the outcome
+// nor the timing tells who is registered.
+func (auth *UseCase) Login(email string, password string) (*Tokens, error) {
 	user, err := auth.repository.FindBy(email)
 	if err != nil {
-		if user == nil {
-			return "", ErrUserNotFound
-		} else {
-			return "", err
+		if errors.Is(err, sql.ErrNoRows) {
+			// Unknown emails take as long as wrong passwords, so the timing does not tell who is registered.
+			entity.MatchDummyPassword(password)
+			return nil, ErrUserNotFound
 		}
+		return nil, err
 	}
 
-	if !user.IsPasswordMatch(password) {
-		return "", ErrUserNotFound
+	isPasswordMatch := user.IsPasswordMatch(password)
+	if user.LockedFor() > 0 {
+		return nil, ErrUserNotFound
+	}
+	if !isPasswordMatch {
+		if err = auth.repository.RecordFailedLogin(user); err != nil {
+			return nil, err
+		}
+		if user.LockOut(auth.options.Lockout) {
+			if err = auth.repository.LockOut(user); err != nil {
+				return nil, err
+			}
+		}
+		return nil, ErrUserNotFound
+	}
+	if user.IsBanned() {
+		return nil, ErrUserBanned
 	}
+	if user.FailedLogins > 0 {
+		user.ResetLoginFailures()
+		if err = auth.repository.UpdateLoginFailures(user); err != nil {
+			return nil, err
+		}
+	}
+
+	return auth.startSession(user)
+}
 
-	tokenString, err := auth.jwtService.CreateTokenStringWithES256(user.Id)
+// Refresh exchanges the refresh token for a new pair within the same session. A refresh token
+// is used only once, so presenting it again means it was stolen: the whole session is revoked.
+func (auth *UseCase) Refresh(rawRefreshToken string) (*Tokens, error) {
+	tokenHash := entity.HashRefreshToken(rawRefreshToken)
+	refreshToken, err := auth.sessionRepository.UseRefreshToken(tokenHash)
 	if err != nil {
-		return "", err
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		usedToken, errFind := auth.sessionRepository.FindRefreshToken(tokenHash)
+		if errFind != nil {
+			if errors.Is(errFind, sql.ErrNoRows) {
+				return nil, ErrInvalidRefreshToken
+			}
+			return nil, errFind
+		}
+		if errRevoke := auth.sessionRepository.RevokeSession(usedToken.Session); errRevoke != nil {
+			return nil, errRevoke
+		}
+		return nil, ErrRefreshTokenReused
 	}
 
-	return tokenString, nil
+	if refreshToken.IsExpired() || refreshToken.Session.IsRevoked() {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// The user is loaded again for the roles in the new access token, which may have changed since the login.
+	user, err := auth.repository.FindById(refreshToken.Session.UserId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	if user.IsBanned() {
+		return nil, ErrUserBanned
+	}
+
+	tokens, nextToken, err := auth.issueTokens(refreshToken.Session, user)
+	if err != nil {
+		return nil, err
+	}
+	if err = auth.sessionRepository.CreateRefreshToken(nextToken); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Logout ends the session which issued the access token. A legacy token without a jti belongs to no
+// session known, so it ends every session of the user.
+func (auth *UseCase) Logout(user *entity.User, token *serviceJwt.Token) error {
+	if token.Jti == "" {
+		return auth.sessionRepository.RevokeUserSessions(user)
+	}
+
+	if err := auth.sessionRepository.RevokeAccessToken(token.Jti, token.Exp); err != nil {
+		return err
+	}
+
+	findSession := auth.sessionRepository.FindSession
+	sessionId := token.Sid
+	if sessionId == "" {
+		findSession, sessionId = auth.sessionRepository.FindSessionByAccessJti, token.Jti
+	}
+	session, err := findSession(sessionId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	return auth.sessionRepository.RevokeSession(session)
+}
+
+// LogoutAll ends every session of the user, on all devices.
+func (auth *UseCase) LogoutAll(user *entity.User, token *serviceJwt.Token) error {
+	if token.Jti != "" {
+		if err := auth.sessionRepository.RevokeAccessToken(token.Jti, token.Exp); err != nil {
+			return err
+		}
+	}
+
+	return auth.sessionRepository.RevokeUserSessions(user)
+}
+
+func (auth *UseCase) startSession(user *entity.User) (*Tokens, error) {
+	session := entity.NewSession(user)
+	tokens, refreshToken, err := auth.issueTokens(session, user)
+	if err != nil {
+		return nil, err
+	}
+	if err = auth.sessionRepository.CreateSession(session, refreshToken); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (auth *UseCase) issueTokens(session *entity.Session, user *entity.User) (*Tokens, *entity.RefreshToken, error) {
+	now := time.Now()
+	accessToken := serviceJwt.NewToken(
+		entity.NewTokenId(),
+		user.Id,
+		session.Id,
+		[]string{user.Role},
+		now,
+		now.Add(auth.options.AccessTokenTtl),
+	)
+	accessTokenString, err := auth.jwtService.CreateTokenStringWithES256(accessToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken, rawRefreshToken := entity.NewRefreshToken(
+		session,
+		accessToken.Jti,
+		accessToken.Exp,
+		now.Add(auth.options.RefreshTokenTtl),
+	)
+
+	return &Tokens{
+		AccessToken:           accessTokenString,
+		AccessTokenExpiresAt:  accessToken.Exp,
+		RefreshToken:          rawRefreshToken,
+		RefreshTokenExpiresAt: refreshToken.ExpiresAt,
+	}, refreshToken, nil
 }
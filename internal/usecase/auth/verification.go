@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/infra/mailer"
+	serviceJwt "github.com/Markard/wordka/internal/infra/service/jwt"
+	"net/url"
+	"time"
+)
+
+var (
+	ErrInvalidVerificationToken = errors.New("verification token is invalid or expired")
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrVerificationNotSent      = errors.New("verification email could not be sent")
+)
+
+// ResendThrottledError is returned when a verification email was sent too recently to send another one.
+type ResendThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ResendThrottledError) Error() string {
+	return fmt.Sprintf("verification email was sent recently, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+// VerifyEmail confirms the email the token was sent to, unless the user has changed it since.
+func (auth *UseCase) VerifyEmail(tokenString string) (*entity.User, error) {
+	token, err := auth.jwtService.VerifyActionTokenStringWithES256(tokenString, serviceJwt.ActionVerifyEmail)
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	user, err := auth.repository.FindById(token.Sub)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidVerificationToken
+		}
+		return nil, err
+	}
+	if user.Email != token.Email {
+		return nil, ErrInvalidVerificationToken
+	}
+	if user.IsEmailVerified() {
+		return user, nil
+	}
+
+	user.VerifyEmail()
+	if err = auth.repository.UpdateEmailVerified(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ResendVerification sends the verification email again, at most once per the resend period.
+func (auth *UseCase) ResendVerification(user *entity.User) error {
+	if user.IsEmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+	period := auth.options.VerificationResendPeriod
+	if wait := user.VerificationResendIn(period); wait > 0 {
+		return &ResendThrottledError{RetryAfter: wait}
+	}
+
+	sentAt := user.EmailVerificationSentAt
+	if err := auth.repository.ClaimVerificationResend(user, period); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// A concurrent request has just sent one.
+			return &ResendThrottledError{RetryAfter: period}
+		}
+		return err
+	}
+	if err := auth.sendVerificationEmail(user); err != nil {
+		// The email was not sent, so it must not hold the next attempt back.
+		user.EmailVerificationSentAt = sentAt
+		if errUpdate := auth.repository.UpdateVerificationSent(user); errUpdate != nil {
+			return errors.Join(err, errUpdate)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// SendVerification emails a link verifying the current email of the user.
+func (auth *UseCase) SendVerification(user *entity.User) error {
+	if err := auth.sendVerificationEmail(user); err != nil {
+		return err
+	}
+
+	user.MarkVerificationSent()
+	return auth.repository.UpdateVerificationSent(user)
+}
+
+func (auth *UseCase) sendVerificationEmail(user *entity.User) error {
+	token := serviceJwt.NewActionToken(
+		serviceJwt.ActionVerifyEmail,
+		user.Id,
+		user.Email,
+		"",
+		time.Now().Add(auth.options.VerificationTokenTtl),
+	)
+	tokenString, err := auth.jwtService.CreateActionTokenStringWithES256(token)
+	if err != nil {
+		return err
+	}
+
+	link := auth.options.VerificationUrl + "?token=" + url.QueryEscape(tokenString)
+	return auth.mailer.Send(&mailer.Message{
+		To:      user.Email,
+		Subject: "Подтвердите адрес почты",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nЧтобы подтвердить адрес почты в Вордке, перейдите по ссылке:\n%s\n\n"+
+				"Ссылка действует до %s. Если вы не регистрировались, просто проигнорируйте это письмо.\n",
+			user.Name,
+			link,
+			token.Exp.Format("02.01.2006 15:04 MST"),
+		),
+	})
+}
@@ -0,0 +1,129 @@
+package dictionary
+
+import (
+	"github.com/Markard/wordka/internal/entity"
+)
+
+const (
+	ImportAdded    = "added"
+	ImportSkipped  = "skipped"
+	ImportRejected = "rejected"
+)
+
+// Entry is a word read from a word list together with the metadata the list provides.
+type Entry struct {
+	Line        int
+	Word        string
+	IsAnswer    bool
+	IsOffensive bool
+	Difficulty  int
+	// Invalid explains why the metadata of the entry could not be read.
+	Invalid string
+}
+
+// ImportItem is the outcome of importing a single entry.
+type ImportItem struct {
+	Status string
+	Line   int
+	Raw    string
+	Word   string
+	Reason string
+
+	word *entity.Word
+}
+
+// ImportReport lists the outcome of every entry in the order of the word list.
+type ImportReport struct {
+	Items []*ImportItem
+}
+
+func (r *ImportReport) Count(status string) int {
+	count := 0
+	for _, item := range r.Items {
+		if item.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Import adds the playable words of the list which are not in the dictionary yet and records
+// the source as the provenance of every playable word of the list. Words already in the dictionary
+// are skipped as they are, their flags are curated separately.
+// In a dry run the report is built the same way but nothing is saved.
+func (p *UseCase) Import(source string, entries []Entry, dryRun bool) (*ImportReport, error) {
+	report := &ImportReport{Items: make([]*ImportItem, 0, len(entries))}
+	candidates := make([]*ImportItem, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+
+	for _, entry := range entries {
+		item := &ImportItem{Line: entry.Line, Raw: entry.Word, Word: entry.Word}
+		report.Items = append(report.Items, item)
+
+		if entry.Invalid != "" {
+			item.Status, item.Reason = ImportRejected, entry.Invalid
+			continue
+		}
+		normalized, err := entity.NormalizeWord(entry.Word)
+		if err != nil {
+			item.Status, item.Reason = ImportRejected, err.Error()
+			continue
+		}
+		item.Word = normalized
+		if seen[normalized] {
+			item.Status, item.Reason = ImportSkipped, "duplicate in the list"
+			continue
+		}
+		seen[normalized] = true
+
+		item.word, err = newImportedWord(normalized, entry)
+		if err != nil {
+			item.Status, item.Reason = ImportRejected, err.Error()
+			continue
+		}
+		candidates = append(candidates, item)
+	}
+
+	words := make([]string, 0, len(candidates))
+	for _, item := range candidates {
+		words = append(words, item.Word)
+	}
+	existing, err := p.repository.FindExistingWords(words)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make([]*entity.Word, 0, len(candidates))
+	for _, item := range candidates {
+		found = append(found, item.word)
+		if existing[item.Word] {
+			item.Status, item.Reason = ImportSkipped, "already in the dictionary"
+			continue
+		}
+		item.Status = ImportAdded
+	}
+
+	if !dryRun && len(found) > 0 {
+		if err := p.repository.SaveFoundWords(source, found); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+func newImportedWord(normalized string, entry Entry) (*entity.Word, error) {
+	word := entity.NewWord(normalized)
+	word.MarkOffensive(entry.IsOffensive)
+	if entry.IsAnswer {
+		if err := word.Promote(); err != nil {
+			return nil, err
+		}
+	}
+	if err := word.SetDifficulty(entry.Difficulty); err != nil {
+		return nil, err
+	}
+
+	return word, nil
+}
@@ -0,0 +1,103 @@
+package dictionary
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/Markard/wordka/internal/entity"
+)
+
+var ErrWordNotFound = errors.New("the word is not in the dictionary")
+
+type IDictionaryRepository interface {
+	FindWord(word string) (*entity.Word, error)
+	UpdateWord(word *entity.Word) error
+	FindExistingWords(words []string) (map[string]bool, error)
+	SaveFoundWords(source string, words []*entity.Word) error
+	FindSingleSourceWords(source string) ([]*entity.WordSource, error)
+}
+
+// UseCase curates the dictionary: which words are accepted as guesses and which can become answers.
+type UseCase struct {
+	repository IDictionaryRepository
+}
+
+func NewDictionaryUseCase(repository IDictionaryRepository) *UseCase {
+	return &UseCase{repository: repository}
+}
+
+func (p *UseCase) Promote(word string) (*entity.Word, error) {
+	return p.update(word, (*entity.Word).Promote)
+}
+
+func (p *UseCase) Demote(word string) (*entity.Word, error) {
+	return p.update(word, func(w *entity.Word) error {
+		w.Demote()
+		return nil
+	})
+}
+
+func (p *UseCase) Enable(word string) (*entity.Word, error) {
+	return p.update(word, func(w *entity.Word) error {
+		w.Enable()
+		return nil
+	})
+}
+
+func (p *UseCase) Disable(word string) (*entity.Word, error) {
+	return p.update(word, func(w *entity.Word) error {
+		w.Disable()
+		return nil
+	})
+}
+
+func (p *UseCase) MarkOffensive(word string, isOffensive bool) (*entity.Word, error) {
+	return p.update(word, func(w *entity.Word) error {
+		w.MarkOffensive(isOffensive)
+		return nil
+	})
+}
+
+func (p *UseCase) SetDifficulty(word string, difficulty int) (*entity.Word, error) {
+	return p.update(word, func(w *entity.Word) error {
+		return w.SetDifficulty(difficulty)
+	})
+}
+
+func (p *UseCase) update(wordStr string, change func(word *entity.Word) error) (*entity.Word, error) {
+	normalized, err := entity.NormalizeWord(wordStr)
+	if err != nil {
+		return nil, ErrWordNotFound
+	}
+
+	word, err := p.repository.FindWord(normalized)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWordNotFound
+		}
+		return nil, err
+	}
+
+	if err := change(word); err != nil {
+		return nil, err
+	}
+	if err := p.repository.UpdateWord(word); err != nil {
+		return nil, err
+	}
+
+	return word, nil
+}
+
+// FindSingleSourceWords lists the words only one source vouches for, which deserve a manual review.
+func (p *UseCase) FindSingleSourceWords(source string) ([]*entity.WordSource, error) {
+	return p.repository.FindSingleSourceWords(source)
+}
+
+// SaveFoundWords adds the words harvested from the source to the dictionary as accepted guesses.
+func (p *UseCase) SaveFoundWords(source string, words []string) error {
+	found := make([]*entity.Word, 0, len(words))
+	for _, word := range words {
+		found = append(found, entity.NewWord(word))
+	}
+
+	return p.repository.SaveFoundWords(source, found)
+}
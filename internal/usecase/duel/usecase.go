@@ -0,0 +1,126 @@
+package duel
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/repo"
+)
+
+var (
+	ErrDuelNotFound       = errors.New("duel not found")
+	ErrDuelOwnInvite      = errors.New("you cannot join your own duel")
+	ErrDuelAlreadyStarted = errors.New("the duel already has an opponent")
+	ErrDuelGameFinished   = errors.New("your game in this duel is already finished")
+	ErrDuelNotStarted     = errors.New("the duel is waiting for an opponent")
+	ErrIncorrectWord      = errors.New("the word you entered is not a noun from the dictionary")
+	ErrNoWordsFound       = errors.New("no words found")
+)
+
+type IDuelRepository interface {
+	CreateDuel(word *entity.Word, creator *entity.User, guessLimit int8) (*entity.Duel, error)
+	JoinDuel(inviteCode string, opponent *entity.User) (*entity.Duel, error)
+	FindDuel(user *entity.User, id int64) (*entity.Duel, error)
+}
+
+type IGameRepository interface {
+	FindRandomWord(length int) (*entity.Word, error)
+	FindWord(word string) (*entity.Word, error)
+	AddGuessForDuelGame(user *entity.User, duelId int64, word *entity.Word) (*entity.Game, error)
+}
+
+type IEventPublisher interface {
+	Publish(event *entity.Event)
+}
+
+type UseCase struct {
+	repository     IDuelRepository
+	gameRepository IGameRepository
+	publisher      IEventPublisher
+	rules          entity.GameRules
+}
+
+func NewDuelUseCase(
+	repository IDuelRepository,
+	gameRepository IGameRepository,
+	publisher IEventPublisher,
+	rules entity.GameRules,
+) *UseCase {
+	return &UseCase{repository: repository, gameRepository: gameRepository, publisher: publisher, rules: rules}
+}
+
+func (p *UseCase) CreateDuel(user *entity.User) (*entity.Duel, error) {
+	randomWord, err := p.gameRepository.FindRandomWord(p.rules.WordLength)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoWordsFound
+		} else {
+			return nil, err
+		}
+	}
+
+	return p.repository.CreateDuel(randomWord, user, p.rules.GuessLimit)
+}
+
+func (p *UseCase) JoinDuel(user *entity.User, inviteCode string) (*entity.Duel, error) {
+	duel, err := p.repository.JoinDuel(inviteCode, user)
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrDuelNotFound):
+			return nil, ErrDuelNotFound
+		case errors.Is(err, entity.ErrDuelOwnInvite):
+			return nil, ErrDuelOwnInvite
+		case errors.Is(err, entity.ErrDuelAlreadyStarted):
+			return nil, ErrDuelAlreadyStarted
+		}
+		return nil, err
+	}
+
+	return duel, nil
+}
+
+func (p *UseCase) FindDuel(user *entity.User, id int64) (*entity.Duel, error) {
+	duel, err := p.repository.FindDuel(user, id)
+	if err != nil {
+		if errors.Is(err, repo.ErrDuelNotFound) {
+			return nil, ErrDuelNotFound
+		}
+		return nil, err
+	}
+
+	return duel, nil
+}
+
+func (p *UseCase) Guess(user *entity.User, id int64, wordStr string) (*entity.Duel, error) {
+	var word *entity.Word
+	if normalized, err := entity.NormalizeWord(wordStr); err == nil {
+		word, _ = p.gameRepository.FindWord(normalized)
+	}
+	if word == nil {
+		return nil, ErrIncorrectWord
+	}
+
+	duel, err := p.FindDuel(user, id)
+	if err != nil {
+		return nil, err
+	}
+	if !duel.IsStarted() {
+		return nil, ErrDuelNotStarted
+	}
+
+	game, err := p.gameRepository.AddGuessForDuelGame(user, duel.Id, word)
+	if err != nil {
+		if errors.Is(err, repo.ErrDuelGameNotFound) {
+			return nil, ErrDuelGameFinished
+		}
+		return nil, err
+	}
+	for _, event := range entity.NewGameEvents(game) {
+		p.publisher.Publish(event)
+	}
+	if opponent := duel.OpponentOf(user.Id); opponent != nil {
+		p.publisher.Publish(entity.NewDuelOpponentMovedEvent(game, opponent.Id))
+	}
+
+	return p.FindDuel(user, id)
+}
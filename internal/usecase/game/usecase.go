@@ -1,33 +1,71 @@
 package game
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"github.com/Markard/wordka/internal/entity"
 	"github.com/Markard/wordka/internal/repo"
+	"time"
 )
 
 var (
 	ErrCurrentGameNotFound      = errors.New("the current user is not playing any game now")
-	ErrIncorrectWord            = errors.New("the word you entered is not a 5-letter noun")
+	ErrIncorrectWord            = errors.New("the word you entered is not a noun from the dictionary")
 	ErrCurrentGameAlreadyExists = errors.New("the current user is already playing a game")
 	ErrNoWordsFound             = errors.New("no words found")
+	ErrDailyPuzzleNotAvailable  = errors.New("the daily puzzle for this date is not available yet")
+	ErrDailyGameNotFound        = errors.New("the current user has not played the daily puzzle for this date")
+	ErrDailyGameAlreadyPlayed   = errors.New("the current user has already played the daily puzzle for this date")
+	ErrDailyGameFinished        = errors.New("the daily puzzle for this date is already finished")
+	ErrGameNotFound             = errors.New("game not found")
 )
 
 type IGameRepository interface {
 	FindCurrentGame(currentUser *entity.User) (*entity.Game, error)
 	IsCurrentGameExists(currentUser *entity.User) (bool, error)
-	CreateGame(word *entity.Word, currentUser *entity.User) (*entity.Game, error)
-	FindRandomWord() (*entity.Word, error)
+	CreateGame(word *entity.Word, currentUser *entity.User, options entity.GameOptions) (*entity.Game, error)
+	FindRandomWord(length int) (*entity.Word, error)
 	FindWord(word string) (*entity.Word, error)
 	AddGuessForCurrentGame(user *entity.User, word *entity.Word) (*entity.Game, error)
+	FindDailyPuzzle(date time.Time) (*entity.DailyPuzzle, error)
+	FindWordForDate(date time.Time, length int) (*entity.Word, error)
+	CreateDailyPuzzle(date time.Time, word *entity.Word) (*entity.DailyPuzzle, error)
+	FindDailyGame(user *entity.User, puzzle *entity.DailyPuzzle) (*entity.Game, error)
+	CreateDailyGame(puzzle *entity.DailyPuzzle, user *entity.User, options entity.GameOptions) (*entity.Game, error)
+	AddGuessForDailyGame(user *entity.User, puzzle *entity.DailyPuzzle, word *entity.Word) (*entity.Game, error)
+	SurrenderCurrentGame(user *entity.User) (*entity.Game, error)
+	FindGames(user *entity.User, filter entity.GameFilter, beforeId int64, limit int) ([]*entity.Game, error)
+	FindGame(user *entity.User, id int64) (*entity.Game, error)
+}
+
+type IEventPublisher interface {
+	Publish(event *entity.Event)
+	PublishLocal(event *entity.Event)
 }
 
 type UseCase struct {
-	repository IGameRepository
+	repository    IGameRepository
+	publisher     IEventPublisher
+	dailyLocation *time.Location
+	practiceRules entity.GameRules
+	dailyRules    entity.GameRules
 }
 
-func NewGameUseCase(repository IGameRepository) *UseCase {
-	return &UseCase{repository: repository}
+func NewGameUseCase(
+	repository IGameRepository,
+	publisher IEventPublisher,
+	dailyLocation *time.Location,
+	practiceRules entity.GameRules,
+	dailyRules entity.GameRules,
+) *UseCase {
+	return &UseCase{
+		repository:    repository,
+		publisher:     publisher,
+		dailyLocation: dailyLocation,
+		practiceRules: practiceRules,
+		dailyRules:    dailyRules,
+	}
 }
 
 func (p *UseCase) FindCurrentGame(user *entity.User) (*entity.Game, error) {
@@ -43,7 +81,7 @@ func (p *UseCase) FindCurrentGame(user *entity.User) (*entity.Game, error) {
 	return game, nil
 }
 
-func (p *UseCase) CreateGame(user *entity.User) (*entity.Game, error) {
+func (p *UseCase) CreateGame(user *entity.User, options entity.GameOptions) (*entity.Game, error) {
 	isExists, err := p.repository.IsCurrentGameExists(user)
 	if err != nil {
 		return nil, err
@@ -53,16 +91,22 @@ func (p *UseCase) CreateGame(user *entity.User) (*entity.Game, error) {
 		return nil, ErrCurrentGameAlreadyExists
 	}
 
-	randomWord, err := p.repository.FindRandomWord()
+	// The player may choose the word length of a practice game, the guess limit is up to the mode.
+	if options.WordLength == 0 {
+		options.WordLength = p.practiceRules.WordLength
+	}
+	options.GuessLimit = p.practiceRules.GuessLimit
+
+	randomWord, err := p.repository.FindRandomWord(options.WordLength)
 	if err != nil {
-		if randomWord == nil {
+		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNoWordsFound
 		} else {
 			return nil, err
 		}
 	}
 
-	game, err := p.repository.CreateGame(randomWord, user)
+	game, err := p.repository.CreateGame(randomWord, user, options)
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +115,7 @@ func (p *UseCase) CreateGame(user *entity.User) (*entity.Game, error) {
 }
 
 func (p *UseCase) Guess(user *entity.User, wordStr string) (*entity.Game, error) {
-	word, _ := p.repository.FindWord(wordStr)
+	word := p.findWord(wordStr)
 	if word == nil {
 		return nil, ErrIncorrectWord
 	}
@@ -83,12 +127,194 @@ func (p *UseCase) Guess(user *entity.User, wordStr string) (*entity.Game, error)
 		}
 		return nil, errAddGuess
 	}
+	p.publishGameEvents(game)
+
+	return game, nil
+}
+
+func (p *UseCase) Surrender(user *entity.User) (*entity.Game, error) {
+	game, err := p.repository.SurrenderCurrentGame(user)
+	if err != nil {
+		if errors.Is(err, repo.ErrCurrentGameNotFound) {
+			return nil, ErrCurrentGameNotFound
+		}
+		return nil, err
+	}
+	p.publisher.Publish(entity.NewGameFinishedEvent(game))
+
+	return game, nil
+}
+
+// FindGames returns up to limit+1 games of the user, so the caller can tell whether there is a next page.
+func (p *UseCase) FindGames(user *entity.User, filter entity.GameFilter, beforeId int64, limit int) ([]*entity.Game, error) {
+	return p.repository.FindGames(user, filter, beforeId, limit+1)
+}
+
+func (p *UseCase) FindGame(user *entity.User, id int64) (*entity.Game, error) {
+	game, err := p.repository.FindGame(user, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGameNotFound
+		}
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// Today returns the current date in the timezone daily puzzles are scheduled in.
+func (p *UseCase) Today() time.Time {
+	now := time.Now().In(p.dailyLocation)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, p.dailyLocation)
+}
+
+// ParseDate parses a YYYY-MM-DD date in the timezone daily puzzles are scheduled in.
+func (p *UseCase) ParseDate(date string) (time.Time, error) {
+	return time.ParseInLocation(time.DateOnly, date, p.dailyLocation)
+}
+
+func (p *UseCase) FindDailyGame(user *entity.User, date time.Time) (*entity.Game, error) {
+	puzzle, err := p.findDailyPuzzle(date)
+	if err != nil {
+		if errors.Is(err, ErrDailyPuzzleNotAvailable) {
+			return nil, ErrDailyGameNotFound
+		}
+		return nil, err
+	}
+
+	game, err := p.repository.FindDailyGame(user, puzzle)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDailyGameNotFound
+		}
+		return nil, err
+	}
+
+	return game, nil
+}
+
+func (p *UseCase) CreateDailyGame(user *entity.User, options entity.GameOptions) (*entity.Game, error) {
+	puzzle, err := p.findOrScheduleDailyPuzzle(p.Today())
+	if err != nil {
+		return nil, err
+	}
+
+	options.GuessLimit = p.dailyRules.GuessLimit
+	game, err := p.repository.CreateDailyGame(puzzle, user, options)
+	if err != nil {
+		if errors.Is(err, repo.ErrDailyGameUniqConstraint) {
+			return nil, ErrDailyGameAlreadyPlayed
+		}
+		return nil, err
+	}
+
+	return game, nil
+}
+
+func (p *UseCase) GuessDaily(user *entity.User, date time.Time, wordStr string) (*entity.Game, error) {
+	word := p.findWord(wordStr)
+	if word == nil {
+		return nil, ErrIncorrectWord
+	}
+
+	puzzle, err := p.findDailyPuzzle(date)
+	if err != nil {
+		if errors.Is(err, ErrDailyPuzzleNotAvailable) {
+			return nil, ErrDailyGameNotFound
+		}
+		return nil, err
+	}
+
+	game, err := p.repository.AddGuessForDailyGame(user, puzzle, word)
+	if err != nil {
+		if errors.Is(err, repo.ErrDailyGameNotFound) {
+			if _, errFind := p.repository.FindDailyGame(user, puzzle); errFind == nil {
+				return nil, ErrDailyGameFinished
+			}
+			return nil, ErrDailyGameNotFound
+		}
+		return nil, err
+	}
+	p.publishGameEvents(game)
 
 	return game, nil
 }
 
-func (p *UseCase) is5LetterNoun(word string) bool {
-	w, _ := p.repository.FindWord(word)
+func (p *UseCase) findDailyPuzzle(date time.Time) (*entity.DailyPuzzle, error) {
+	if date.After(p.Today()) {
+		return nil, ErrDailyPuzzleNotAvailable
+	}
+
+	puzzle, err := p.repository.FindDailyPuzzle(date)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDailyPuzzleNotAvailable
+		}
+		return nil, err
+	}
+
+	return puzzle, nil
+}
+
+// findOrScheduleDailyPuzzle returns the puzzle scheduled for the date and schedules one on the fly
+// when nobody did it in advance.
+func (p *UseCase) findOrScheduleDailyPuzzle(date time.Time) (*entity.DailyPuzzle, error) {
+	puzzle, err := p.findDailyPuzzle(date)
+	if err == nil || !errors.Is(err, ErrDailyPuzzleNotAvailable) {
+		return puzzle, err
+	}
+
+	word, err := p.repository.FindWordForDate(date, p.dailyRules.WordLength)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoWordsFound
+		}
+		return nil, err
+	}
+
+	puzzle, err = p.repository.CreateDailyPuzzle(date, word)
+	if err != nil {
+		if errors.Is(err, repo.ErrDailyPuzzleDateUniqConstraint) {
+			return p.findDailyPuzzle(date)
+		}
+		return nil, err
+	}
+
+	return puzzle, nil
+}
+
+// WatchDailyRollover notifies everyone when a new daily puzzle becomes available, until the context is cancelled.
+// Every app instance watches the clock on its own and notifies only its own subscribers.
+func (p *UseCase) WatchDailyRollover(ctx context.Context) {
+	for {
+		next := p.Today().AddDate(0, 0, 1)
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			p.publisher.PublishLocal(entity.NewEvent(entity.EventDailyRollover, map[string]any{
+				"date": next.Format(time.DateOnly),
+			}))
+		}
+	}
+}
+
+func (p *UseCase) publishGameEvents(game *entity.Game) {
+	for _, event := range entity.NewGameEvents(game) {
+		p.publisher.Publish(event)
+	}
+}
+
+// findWord looks the guess up in the dictionary by its normalized spelling, nil means it is not a valid guess.
+func (p *UseCase) findWord(wordStr string) *entity.Word {
+	normalized, err := entity.NormalizeWord(wordStr)
+	if err != nil {
+		return nil
+	}
+	word, _ := p.repository.FindWord(normalized)
 
-	return w != nil
+	return word
 }
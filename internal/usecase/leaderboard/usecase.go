@@ -0,0 +1,113 @@
+package leaderboard
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/Markard/wordka/internal/entity"
+	"slices"
+	"time"
+)
+
+var (
+	ErrUnknownLeaderboard  = errors.New("leaderboard not found")
+	ErrDailyPuzzleNotFound = errors.New("the daily puzzle for this date not found")
+)
+
+var periodKinds = []string{
+	entity.LeaderboardKindWins,
+	entity.LeaderboardKindAvgGuesses,
+	entity.LeaderboardKindStreak,
+}
+
+type ILeaderboardRepository interface {
+	FindPeriodRankings(
+		user *entity.User,
+		kind string,
+		period string,
+		periodStart time.Time,
+		limit int,
+	) ([]*entity.Ranking, error)
+	FindDailyRankings(user *entity.User, puzzle *entity.DailyPuzzle, limit int) ([]*entity.Ranking, error)
+	RebuildLeaderboards() error
+}
+
+type IDailyPuzzleRepository interface {
+	FindDailyPuzzle(date time.Time) (*entity.DailyPuzzle, error)
+}
+
+// Leaderboard is the top of a leaderboard together with the ranking of the current user,
+// which is nil when the user has no ranking on it.
+type Leaderboard struct {
+	Top []*entity.Ranking
+	Me  *entity.Ranking
+}
+
+type UseCase struct {
+	repository       ILeaderboardRepository
+	puzzleRepository IDailyPuzzleRepository
+	dailyLocation    *time.Location
+}
+
+func NewLeaderboardUseCase(
+	repository ILeaderboardRepository,
+	puzzleRepository IDailyPuzzleRepository,
+	dailyLocation *time.Location,
+) *UseCase {
+	return &UseCase{repository: repository, puzzleRepository: puzzleRepository, dailyLocation: dailyLocation}
+}
+
+func (p *UseCase) FindPeriodLeaderboard(user *entity.User, kind string, period string, limit int) (*Leaderboard, error) {
+	if !slices.Contains(periodKinds, kind) || !slices.Contains(entity.LeaderboardPeriods, period) {
+		return nil, ErrUnknownLeaderboard
+	}
+
+	rankings, err := p.repository.FindPeriodRankings(
+		user,
+		kind,
+		period,
+		entity.LeaderboardPeriodStart(period, time.Now(), p.dailyLocation),
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newLeaderboard(user, rankings, limit), nil
+}
+
+func (p *UseCase) FindDailyLeaderboard(user *entity.User, date time.Time, limit int) (*Leaderboard, error) {
+	puzzle, err := p.puzzleRepository.FindDailyPuzzle(date)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDailyPuzzleNotFound
+		}
+		return nil, err
+	}
+
+	rankings, err := p.repository.FindDailyRankings(user, puzzle, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return newLeaderboard(user, rankings, limit), nil
+}
+
+func (p *UseCase) RebuildLeaderboards() error {
+	return p.repository.RebuildLeaderboards()
+}
+
+// newLeaderboard splits rankings fetched as "top N or current user" into the top and the user's own ranking.
+// Ties may put more than limit players into the top; they are all kept.
+func newLeaderboard(user *entity.User, rankings []*entity.Ranking, limit int) *Leaderboard {
+	leaderboard := &Leaderboard{Top: make([]*entity.Ranking, 0, limit)}
+	for _, ranking := range rankings {
+		if ranking.Rank <= limit {
+			leaderboard.Top = append(leaderboard.Top, ranking)
+		}
+		if ranking.UserId == user.Id {
+			leaderboard.Me = ranking
+		}
+	}
+
+	return leaderboard
+}
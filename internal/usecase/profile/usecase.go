@@ -0,0 +1,160 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/repo"
+)
+
+var (
+	ErrEmailAlreadyTaken   = errors.New("user with such email already exists")
+	ErrWrongPassword       = errors.New("current password is incorrect")
+	ErrVerificationNotSent = errors.New("verification email could not be sent")
+)
+
+type IUserRepository interface {
+	UpdateProfile(user *entity.User) error
+}
+
+type IProfileRepository interface {
+	Anonymize(user *entity.User) error
+	FindGames(user *entity.User) ([]*entity.Game, error)
+	FindDuels(user *entity.User) ([]*entity.Duel, error)
+	FindStats(user *entity.User) (*entity.UserStats, error)
+	FindSessions(user *entity.User) ([]*entity.Session, error)
+	FindShareTokens(user *entity.User) ([]*entity.ShareToken, error)
+	FindIdentities(user *entity.User) ([]*entity.UserIdentity, error)
+}
+
+type ISessionRepository interface {
+	RevokeUserSessions(user *entity.User) error
+}
+
+type IVerificationSender interface {
+	SendVerification(user *entity.User) error
+}
+
+// Changes of the profile, nil fields stay as they are.
+type Changes struct {
+	Name     *string
+	Email    *string
+	Locale   *string
+	Timezone *string
+	// CurrentPassword confirms an email change.
+	CurrentPassword string
+}
+
+// Export is all the personal data kept about a user.
+type Export struct {
+	User        *entity.User
+	Stats       *entity.UserStats
+	Games       []*entity.Game
+	Duels       []*entity.Duel
+	Sessions    []*entity.Session
+	ShareTokens []*entity.ShareToken
+	Identities  []*entity.UserIdentity
+}
+
+type UseCase struct {
+	userRepository    IUserRepository
+	repository        IProfileRepository
+	sessionRepository ISessionRepository
+	verifier          IVerificationSender
+}
+
+func NewProfileUseCase(
+	userRepository IUserRepository,
+	repository IProfileRepository,
+	sessionRepository ISessionRepository,
+	verifier IVerificationSender,
+) *UseCase {
+	return &UseCase{
+		userRepository:    userRepository,
+		repository:        repository,
+		sessionRepository: sessionRepository,
+		verifier:          verifier,
+	}
+}
+
+// Update applies the changes to the profile. A new email has to be verified again, a link is sent to it;
+// failing to send it is reported with ErrVerificationNotSent along with the updated user.
+func (p *UseCase) Update(user *entity.User, changes *Changes) (*entity.User, error) {
+	isEmailChanged := changes.Email != nil && *changes.Email != user.Email
+	if isEmailChanged && user.HasPassword() && !user.IsPasswordMatch(changes.CurrentPassword) {
+		return nil, ErrWrongPassword
+	}
+
+	if changes.Name != nil {
+		user.Rename(*changes.Name)
+	}
+	if changes.Locale != nil {
+		if err := user.SetLocale(*changes.Locale); err != nil {
+			return nil, err
+		}
+	}
+	if changes.Timezone != nil {
+		if err := user.SetTimezone(*changes.Timezone); err != nil {
+			return nil, err
+		}
+	}
+	if isEmailChanged {
+		user.ChangeEmail(*changes.Email)
+	}
+
+	if err := p.userRepository.UpdateProfile(user); err != nil {
+		if errors.Is(err, repo.ErrEmailUniqConstraint) {
+			return nil, ErrEmailAlreadyTaken
+		}
+		return nil, err
+	}
+
+	if isEmailChanged {
+		if err := p.verifier.SendVerification(user); err != nil {
+			return user, fmt.Errorf("%w: %w", ErrVerificationNotSent, err)
+		}
+	}
+
+	return user, nil
+}
+
+// Delete anonymizes the account after the password, if the user has one, is confirmed and logs it out everywhere.
+// The games stay for the leaderboards and duels of other players, see entity.User.Anonymize.
+func (p *UseCase) Delete(user *entity.User, password string) error {
+	if user.HasPassword() && !user.IsPasswordMatch(password) {
+		return ErrWrongPassword
+	}
+
+	user.Anonymize()
+	if err := p.repository.Anonymize(user); err != nil {
+		return err
+	}
+
+	return p.sessionRepository.RevokeUserSessions(user)
+}
+
+func (p *UseCase) Export(user *entity.User) (*Export, error) {
+	var err error
+	export := &Export{User: user}
+
+	if export.Stats, err = p.repository.FindStats(user); err != nil {
+		return nil, err
+	}
+	if export.Games, err = p.repository.FindGames(user); err != nil {
+		return nil, err
+	}
+	if export.Duels, err = p.repository.FindDuels(user); err != nil {
+		return nil, err
+	}
+	if export.Sessions, err = p.repository.FindSessions(user); err != nil {
+		return nil, err
+	}
+	if export.ShareTokens, err = p.repository.FindShareTokens(user); err != nil {
+		return nil, err
+	}
+	if export.Identities, err = p.repository.FindIdentities(user); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
@@ -0,0 +1,108 @@
+package share
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/Markard/wordka/internal/entity"
+	"github.com/Markard/wordka/internal/repo"
+)
+
+var (
+	ErrGameNotFound    = errors.New("game not found")
+	ErrGameNotFinished = errors.New("only a finished game can be shared")
+	ErrShareNotFound   = errors.New("the shared result not found or no longer available")
+)
+
+type IShareRepository interface {
+	FindActiveShareToken(game *entity.Game) (*entity.ShareToken, error)
+	CreateShareToken(game *entity.Game) (*entity.ShareToken, error)
+	RevokeShareTokens(game *entity.Game) error
+	FindGameByShareToken(token string) (*entity.Game, error)
+}
+
+type IGameRepository interface {
+	FindGame(user *entity.User, id int64) (*entity.Game, error)
+}
+
+type UseCase struct {
+	repository     IShareRepository
+	gameRepository IGameRepository
+}
+
+func NewShareUseCase(repository IShareRepository, gameRepository IGameRepository) *UseCase {
+	return &UseCase{repository: repository, gameRepository: gameRepository}
+}
+
+// Share returns the active share token of a finished game of the user, creating one if there is none.
+func (p *UseCase) Share(user *entity.User, gameId int64) (*entity.ShareToken, error) {
+	game, err := p.findFinishedGame(user, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	shareToken, err := p.repository.FindActiveShareToken(game)
+	if err == nil || !errors.Is(err, sql.ErrNoRows) {
+		return shareToken, err
+	}
+
+	shareToken, err = p.repository.CreateShareToken(game)
+	if errors.Is(err, repo.ErrShareTokenUniqConstraint) {
+		return p.repository.FindActiveShareToken(game)
+	}
+
+	return shareToken, err
+}
+
+// FindShare returns the active share token of a finished game of the user without creating one.
+func (p *UseCase) FindShare(user *entity.User, gameId int64) (*entity.ShareToken, error) {
+	game, err := p.findFinishedGame(user, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	shareToken, err := p.repository.FindActiveShareToken(game)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrShareNotFound
+		}
+		return nil, err
+	}
+
+	return shareToken, nil
+}
+
+func (p *UseCase) Revoke(user *entity.User, gameId int64) error {
+	game, err := p.findFinishedGame(user, gameId)
+	if err != nil {
+		return err
+	}
+
+	return p.repository.RevokeShareTokens(game)
+}
+
+func (p *UseCase) FindSharedGame(token string) (*entity.Game, error) {
+	game, err := p.repository.FindGameByShareToken(token)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrShareNotFound
+		}
+		return nil, err
+	}
+
+	return game, nil
+}
+
+func (p *UseCase) findFinishedGame(user *entity.User, gameId int64) (*entity.Game, error) {
+	game, err := p.gameRepository.FindGame(user, gameId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGameNotFound
+		}
+		return nil, err
+	}
+	if game.IsPlaying {
+		return nil, ErrGameNotFinished
+	}
+
+	return game, nil
+}
@@ -0,0 +1,37 @@
+package stats
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/Markard/wordka/internal/entity"
+)
+
+type IStatsRepository interface {
+	FindStats(currentUser *entity.User) (*entity.UserStats, error)
+	RebuildStats() (int, error)
+}
+
+type UseCase struct {
+	repository IStatsRepository
+}
+
+func NewStatsUseCase(repository IStatsRepository) *UseCase {
+	return &UseCase{repository: repository}
+}
+
+// FindStats returns the stats of the user, empty ones if the user has not finished any game yet.
+func (p *UseCase) FindStats(user *entity.User) (*entity.UserStats, error) {
+	stats, err := p.repository.FindStats(user)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.NewUserStats(user.Id), nil
+		}
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (p *UseCase) RebuildStats() (int, error) {
+	return p.repository.RebuildStats()
+}
@@ -1,11 +1,23 @@
 package usecase
 
 import (
+	"github.com/Markard/wordka/internal/usecase/admin"
 	"github.com/Markard/wordka/internal/usecase/auth"
+	"github.com/Markard/wordka/internal/usecase/duel"
 	"github.com/Markard/wordka/internal/usecase/game"
+	"github.com/Markard/wordka/internal/usecase/leaderboard"
+	"github.com/Markard/wordka/internal/usecase/profile"
+	"github.com/Markard/wordka/internal/usecase/share"
+	"github.com/Markard/wordka/internal/usecase/stats"
 )
 
 type UseCases struct {
-	AuthUseCase *auth.UseCase
-	GameUseCase *game.UseCase
+	AuthUseCase        *auth.UseCase
+	GameUseCase        *game.UseCase
+	StatsUseCase       *stats.UseCase
+	LeaderboardUseCase *leaderboard.UseCase
+	DuelUseCase        *duel.UseCase
+	ShareUseCase       *share.UseCase
+	ProfileUseCase     *profile.UseCase
+	AdminUseCase       *admin.UseCase
 }
@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+DROP INDEX "uidx__games__user_id__daily_puzzle_id";
+ALTER TABLE "games"
+    DROP COLUMN "daily_puzzle_id",
+    DROP COLUMN "mode";
+DROP TABLE "daily_puzzles";
+COMMIT;
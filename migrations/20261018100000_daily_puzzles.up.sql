@@ -0,0 +1,22 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE "daily_puzzles"
+(
+    "id"         BIGSERIAL    NOT NULL,
+    "date"       DATE         NOT NULL,
+    "word_id"    INT          NOT NULL,
+    "created_at" TIMESTAMP(0) NOT NULL,
+    CONSTRAINT "pidx__daily_puzzles__id" PRIMARY KEY ("id"),
+    CONSTRAINT "uidx__daily_puzzles__date" UNIQUE ("date"),
+    FOREIGN KEY ("word_id") REFERENCES "words" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE
+);
+
+ALTER TABLE "games"
+    ADD COLUMN "mode"            VARCHAR(16) NOT NULL DEFAULT 'practice',
+    ADD COLUMN "daily_puzzle_id" BIGINT,
+    ADD FOREIGN KEY ("daily_puzzle_id") REFERENCES "daily_puzzles" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE;
+CREATE UNIQUE INDEX "uidx__games__user_id__daily_puzzle_id" ON "games" ("user_id", "daily_puzzle_id");
+
+COMMIT;
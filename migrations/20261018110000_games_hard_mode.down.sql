@@ -0,0 +1 @@
+ALTER TABLE "games" DROP COLUMN "is_hard_mode";
@@ -0,0 +1 @@
+ALTER TABLE "games" ADD COLUMN "is_hard_mode" BOOLEAN NOT NULL DEFAULT FALSE;
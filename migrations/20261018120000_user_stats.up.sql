@@ -0,0 +1,14 @@
+CREATE TABLE "user_stats"
+(
+    "user_id"            BIGINT       NOT NULL,
+    "games_played"       INT          NOT NULL DEFAULT 0,
+    "games_won"          INT          NOT NULL DEFAULT 0,
+    "hard_mode_wins"     INT          NOT NULL DEFAULT 0,
+    "current_streak"     INT          NOT NULL DEFAULT 0,
+    "max_streak"         INT          NOT NULL DEFAULT 0,
+    "guess_distribution" INT[]        NOT NULL DEFAULT '{}',
+    "updated_at"         TIMESTAMP(0) NOT NULL,
+    CONSTRAINT "pidx__user_stats__user_id" PRIMARY KEY ("user_id"),
+    FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE
+);
@@ -0,0 +1,4 @@
+BEGIN TRANSACTION;
+DROP TABLE "daily_results";
+DROP TABLE "leaderboard_entries";
+COMMIT;
@@ -0,0 +1,39 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE "leaderboard_entries"
+(
+    "period"         VARCHAR(16)  NOT NULL,
+    "period_start"   DATE         NOT NULL,
+    "user_id"        BIGINT       NOT NULL,
+    "wins"           INT          NOT NULL DEFAULT 0,
+    "win_guesses"    INT          NOT NULL DEFAULT 0,
+    "current_streak" INT          NOT NULL DEFAULT 0,
+    "max_streak"     INT          NOT NULL DEFAULT 0,
+    "updated_at"     TIMESTAMP(0) NOT NULL,
+    CONSTRAINT "pidx__leaderboard_entries__period__period_start__user_id" PRIMARY KEY ("period", "period_start", "user_id"),
+    FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE
+);
+CREATE INDEX "idx__leaderboard_entries__period__period_start__wins"
+    ON "leaderboard_entries" ("period", "period_start", "wins" DESC);
+CREATE INDEX "idx__leaderboard_entries__period__period_start__max_streak"
+    ON "leaderboard_entries" ("period", "period_start", "max_streak" DESC);
+
+CREATE TABLE "daily_results"
+(
+    "daily_puzzle_id" BIGINT       NOT NULL,
+    "user_id"         BIGINT       NOT NULL,
+    "is_won"          BOOLEAN      NOT NULL,
+    "guesses"         SMALLINT     NOT NULL,
+    "solve_seconds"   INT          NOT NULL,
+    "created_at"      TIMESTAMP(0) NOT NULL,
+    CONSTRAINT "pidx__daily_results__daily_puzzle_id__user_id" PRIMARY KEY ("daily_puzzle_id", "user_id"),
+    FOREIGN KEY ("daily_puzzle_id") REFERENCES "daily_puzzles" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE,
+    FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE
+);
+CREATE INDEX "idx__daily_results__daily_puzzle_id__guesses__solve_seconds"
+    ON "daily_results" ("daily_puzzle_id", "guesses", "solve_seconds") WHERE "is_won";
+
+COMMIT;
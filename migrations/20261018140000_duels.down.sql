@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+DROP INDEX "uidx__games__duel_id__user_id";
+ALTER TABLE "games" DROP COLUMN "duel_id";
+DROP TABLE "duels";
+COMMIT;
@@ -0,0 +1,32 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE "duels"
+(
+    "id"          BIGSERIAL    NOT NULL,
+    "invite_code" VARCHAR(16)  NOT NULL,
+    "word_id"     INT          NOT NULL,
+    "creator_id"  BIGINT       NOT NULL,
+    "opponent_id" BIGINT,
+    "winner_id"   BIGINT,
+    "status"      VARCHAR(16)  NOT NULL,
+    "created_at"  TIMESTAMP(0) NOT NULL,
+    "updated_at"  TIMESTAMP(0) NOT NULL,
+    CONSTRAINT "pidx__duels__id" PRIMARY KEY ("id"),
+    CONSTRAINT "uidx__duels__invite_code" UNIQUE ("invite_code"),
+    FOREIGN KEY ("word_id") REFERENCES "words" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE,
+    FOREIGN KEY ("creator_id") REFERENCES "users" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE,
+    FOREIGN KEY ("opponent_id") REFERENCES "users" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE,
+    FOREIGN KEY ("winner_id") REFERENCES "users" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE
+);
+
+ALTER TABLE "games"
+    ADD COLUMN "duel_id" BIGINT,
+    ADD FOREIGN KEY ("duel_id") REFERENCES "duels" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE;
+CREATE UNIQUE INDEX "uidx__games__duel_id__user_id" ON "games" ("duel_id", "user_id");
+
+COMMIT;
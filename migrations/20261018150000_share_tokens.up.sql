@@ -0,0 +1,13 @@
+CREATE TABLE "share_tokens"
+(
+    "id"         BIGSERIAL    NOT NULL,
+    "game_id"    BIGINT       NOT NULL,
+    "token"      VARCHAR(32)  NOT NULL,
+    "created_at" TIMESTAMP(0) NOT NULL,
+    "revoked_at" TIMESTAMP(0),
+    CONSTRAINT "pidx__share_tokens__id" PRIMARY KEY ("id"),
+    CONSTRAINT "uidx__share_tokens__token" UNIQUE ("token"),
+    FOREIGN KEY ("game_id") REFERENCES "games" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE
+);
+CREATE UNIQUE INDEX "uidx__share_tokens__game_id__active" ON "share_tokens" ("game_id") WHERE "revoked_at" IS NULL;
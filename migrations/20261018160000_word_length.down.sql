@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+ALTER TABLE "duels" DROP COLUMN "guess_limit";
+DROP INDEX "idx__words__length";
+ALTER TABLE "words" DROP COLUMN "length";
+ALTER TABLE "words" ALTER COLUMN "word" TYPE VARCHAR(5);
+COMMIT;
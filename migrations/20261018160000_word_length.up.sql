@@ -0,0 +1,12 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE "words" ALTER COLUMN "word" TYPE VARCHAR(8);
+ALTER TABLE "words" ADD COLUMN "length" SMALLINT;
+UPDATE "words" SET "length" = CHAR_LENGTH("word");
+ALTER TABLE "words" ALTER COLUMN "length" SET NOT NULL;
+CREATE INDEX "idx__words__length" ON "words" ("length");
+
+ALTER TABLE "duels" ADD COLUMN "guess_limit" SMALLINT NOT NULL DEFAULT 6;
+ALTER TABLE "duels" ALTER COLUMN "guess_limit" DROP DEFAULT;
+
+COMMIT;
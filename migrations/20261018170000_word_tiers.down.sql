@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+DROP INDEX "idx__words__length__answer";
+ALTER TABLE "words" DROP COLUMN "difficulty";
+ALTER TABLE "words" DROP COLUMN "is_offensive";
+ALTER TABLE "words" DROP COLUMN "is_answer";
+ALTER TABLE "words" DROP COLUMN "is_enabled";
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE "words" ADD COLUMN "is_enabled" BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE "words" ADD COLUMN "is_answer" BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE "words" ADD COLUMN "is_offensive" BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE "words" ADD COLUMN "difficulty" SMALLINT;
+ALTER TABLE "words" ADD CONSTRAINT "chk__words__difficulty" CHECK ("difficulty" BETWEEN 1 AND 5);
+
+-- Every word used to be a possible answer, keep it so until the answer list is curated.
+UPDATE "words" SET "is_answer" = TRUE;
+
+CREATE INDEX "idx__words__length__answer" ON "words" ("length")
+    WHERE "is_answer" AND "is_enabled" AND NOT "is_offensive";
+
+COMMIT;
@@ -0,0 +1,2 @@
+-- The original spelling of the words is lost, there is nothing to restore.
+SELECT 1;
@@ -0,0 +1,18 @@
+BEGIN TRANSACTION;
+
+-- The dictionary spells "ё" as "е" from now on, words are looked up by the normalized spelling.
+UPDATE "words"
+SET "word" = REPLACE("word", 'ё', 'е')
+WHERE "id" IN (SELECT DISTINCT ON (REPLACE(w."word", 'ё', 'е')) w."id"
+               FROM "words" AS w
+               WHERE w."word" LIKE '%ё%'
+                 AND NOT EXISTS (SELECT 1 FROM "words" AS e WHERE e."word" = REPLACE(w."word", 'ё', 'е'))
+               ORDER BY REPLACE(w."word", 'ё', 'е'), w."id");
+
+-- The rest duplicate a word already spelled with "е" and can be neither guessed nor answered.
+UPDATE "words"
+SET "is_enabled" = FALSE,
+    "is_answer"  = FALSE
+WHERE "word" LIKE '%ё%';
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE "word_sources"
+(
+    "word_id"       INT          NOT NULL,
+    "source"        VARCHAR(64)  NOT NULL,
+    "first_seen_at" TIMESTAMP(0) NOT NULL,
+    "last_seen_at"  TIMESTAMP(0) NOT NULL,
+    CONSTRAINT "pidx__word_sources__word_id__source" PRIMARY KEY ("word_id", "source"),
+    FOREIGN KEY ("word_id") REFERENCES "words" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE
+);
+CREATE INDEX "idx__word_sources__source" ON "word_sources" ("source");
+
+-- The bezbukv parser used to be the only way to fill the dictionary.
+INSERT INTO "word_sources" ("word_id", "source", "first_seen_at", "last_seen_at")
+SELECT "id", 'bezbukv', "created_at", "created_at"
+FROM "words";
+
+COMMIT;
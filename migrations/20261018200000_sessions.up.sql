@@ -0,0 +1,38 @@
+CREATE TABLE "sessions"
+(
+    "id"         VARCHAR(32)  NOT NULL,
+    "user_id"    BIGINT       NOT NULL,
+    "created_at" TIMESTAMP(0) NOT NULL,
+    "revoked_at" TIMESTAMP(0),
+    CONSTRAINT "pidx__sessions__id" PRIMARY KEY ("id"),
+    FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE
+);
+CREATE INDEX "idx__sessions__user_id" ON "sessions" ("user_id") WHERE "revoked_at" IS NULL;
+
+CREATE TABLE "refresh_tokens"
+(
+    "id"                BIGSERIAL    NOT NULL,
+    "session_id"        VARCHAR(32)  NOT NULL,
+    "token_hash"        CHAR(64)     NOT NULL,
+    "access_jti"        VARCHAR(32)  NOT NULL,
+    "access_expires_at" TIMESTAMP(0) NOT NULL,
+    "expires_at"        TIMESTAMP(0) NOT NULL,
+    "used_at"           TIMESTAMP(0),
+    "created_at"        TIMESTAMP(0) NOT NULL,
+    CONSTRAINT "pidx__refresh_tokens__id" PRIMARY KEY ("id"),
+    CONSTRAINT "uidx__refresh_tokens__token_hash" UNIQUE ("token_hash"),
+    FOREIGN KEY ("session_id") REFERENCES "sessions" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE
+);
+CREATE INDEX "idx__refresh_tokens__session_id" ON "refresh_tokens" ("session_id");
+CREATE INDEX "idx__refresh_tokens__access_jti" ON "refresh_tokens" ("access_jti");
+
+CREATE TABLE "revoked_tokens"
+(
+    "jti"        VARCHAR(32)  NOT NULL,
+    "expires_at" TIMESTAMP(0) NOT NULL,
+    "revoked_at" TIMESTAMP(0) NOT NULL,
+    CONSTRAINT "pidx__revoked_tokens__jti" PRIMARY KEY ("jti")
+);
+CREATE INDEX "idx__revoked_tokens__expires_at" ON "revoked_tokens" ("expires_at");
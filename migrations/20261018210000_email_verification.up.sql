@@ -0,0 +1 @@
+ALTER TABLE "users" ADD COLUMN "email_verification_sent_at" TIMESTAMP(0);
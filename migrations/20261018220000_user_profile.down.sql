@@ -0,0 +1,4 @@
+ALTER TABLE "users"
+    DROP COLUMN "locale",
+    DROP COLUMN "timezone",
+    DROP COLUMN "deleted_at";
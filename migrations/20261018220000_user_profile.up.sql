@@ -0,0 +1,4 @@
+ALTER TABLE "users"
+    ADD COLUMN "locale"     VARCHAR(8)  NOT NULL DEFAULT 'ru',
+    ADD COLUMN "timezone"   VARCHAR(64) NOT NULL DEFAULT 'Europe/Moscow',
+    ADD COLUMN "deleted_at" TIMESTAMP(0);
@@ -0,0 +1,12 @@
+BEGIN TRANSACTION;
+
+DROP TABLE "audit_log";
+
+ALTER TABLE "users"
+    DROP CONSTRAINT "chk__users__role",
+    DROP COLUMN "role",
+    DROP COLUMN "banned_at",
+    DROP COLUMN "ban_reason",
+    DROP COLUMN "stats_reset_at";
+
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE "users"
+    ADD COLUMN "role"           VARCHAR(16) NOT NULL DEFAULT 'player',
+    ADD COLUMN "banned_at"      TIMESTAMP(0),
+    ADD COLUMN "ban_reason"     VARCHAR(255),
+    ADD COLUMN "stats_reset_at" TIMESTAMP(0),
+    ADD CONSTRAINT "chk__users__role" CHECK ("role" IN ('player', 'moderator', 'admin'));
+
+CREATE TABLE "audit_log"
+(
+    "id"          BIGSERIAL    NOT NULL,
+    "actor_id"    BIGINT       NOT NULL,
+    "action"      VARCHAR(64)  NOT NULL,
+    "target_type" VARCHAR(32)  NOT NULL,
+    "target"      VARCHAR(255) NOT NULL,
+    "details"     JSONB        NOT NULL DEFAULT '{}',
+    "created_at"  TIMESTAMP(0) NOT NULL,
+    CONSTRAINT "pidx__audit_log__id" PRIMARY KEY ("id"),
+    FOREIGN KEY ("actor_id") REFERENCES "users" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE
+);
+CREATE INDEX "idx__audit_log__target" ON "audit_log" ("target_type", "target");
+
+COMMIT;
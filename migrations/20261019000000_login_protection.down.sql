@@ -0,0 +1,9 @@
+BEGIN TRANSACTION;
+
+DROP TABLE "rate_limit_buckets";
+
+ALTER TABLE "users"
+    DROP COLUMN "failed_logins",
+    DROP COLUMN "locked_until";
+
+COMMIT;
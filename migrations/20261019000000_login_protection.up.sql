@@ -0,0 +1,17 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE "users"
+    ADD COLUMN "failed_logins" INT NOT NULL DEFAULT 0,
+    ADD COLUMN "locked_until"  TIMESTAMP(0);
+
+CREATE TABLE "rate_limit_buckets"
+(
+    "key"        VARCHAR(320)     NOT NULL,
+    "tokens"     DOUBLE PRECISION NOT NULL,
+    "updated_at" TIMESTAMP(6)     NOT NULL,
+    "expires_at" TIMESTAMP(0)     NOT NULL,
+    CONSTRAINT "pidx__rate_limit_buckets__key" PRIMARY KEY ("key")
+);
+CREATE INDEX "idx__rate_limit_buckets__expires_at" ON "rate_limit_buckets" ("expires_at");
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN TRANSACTION;
+
+DROP TABLE "external_logins";
+DROP TABLE "user_identities";
+
+UPDATE "users" SET "password" = '' WHERE "password" IS NULL;
+ALTER TABLE "users"
+    ALTER COLUMN "password" SET NOT NULL;
+
+COMMIT;
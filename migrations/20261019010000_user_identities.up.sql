@@ -0,0 +1,36 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE "users"
+    ALTER COLUMN "password" DROP NOT NULL;
+UPDATE "users" SET "password" = NULL WHERE "password" = '';
+
+CREATE TABLE "user_identities"
+(
+    "id"            BIGSERIAL    NOT NULL,
+    "user_id"       BIGINT       NOT NULL,
+    "provider"      VARCHAR(32)  NOT NULL,
+    "subject"       VARCHAR(255) NOT NULL,
+    "email"         VARCHAR(320),
+    "created_at"    TIMESTAMP(0) NOT NULL,
+    "last_login_at" TIMESTAMP(0) NOT NULL,
+    CONSTRAINT "pidx__user_identities__id" PRIMARY KEY ("id"),
+    CONSTRAINT "uidx__user_identities__provider__subject" UNIQUE ("provider", "subject"),
+    FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE RESTRICT ON UPDATE RESTRICT
+        NOT DEFERRABLE INITIALLY IMMEDIATE
+);
+CREATE INDEX "idx__user_identities__user_id" ON "user_identities" ("user_id");
+
+CREATE TABLE "external_logins"
+(
+    "state_hash"    CHAR(64)     NOT NULL,
+    "provider"      VARCHAR(32)  NOT NULL,
+    "code_verifier" VARCHAR(64)  NOT NULL,
+    "nonce"         VARCHAR(64)  NOT NULL,
+    "expires_at"    TIMESTAMP(0) NOT NULL,
+    "used_at"       TIMESTAMP(0),
+    "created_at"    TIMESTAMP(0) NOT NULL,
+    CONSTRAINT "pidx__external_logins__state_hash" PRIMARY KEY ("state_hash")
+);
+CREATE INDEX "idx__external_logins__expires_at" ON "external_logins" ("expires_at");
+
+COMMIT;
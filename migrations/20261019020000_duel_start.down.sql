@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE "duels" DROP COLUMN "started_at";
+
+COMMIT;
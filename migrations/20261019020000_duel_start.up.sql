@@ -0,0 +1,9 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE "duels" ADD COLUMN "started_at" TIMESTAMP(0);
+UPDATE "duels" d
+SET "started_at" = g."created_at"
+FROM "games" g
+WHERE g."duel_id" = d."id" AND g."user_id" = d."opponent_id";
+
+COMMIT;
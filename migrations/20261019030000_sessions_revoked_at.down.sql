@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE "users"
+    DROP COLUMN "sessions_revoked_at";
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE "users"
+    ADD COLUMN "sessions_revoked_at" TIMESTAMP(0);
+
+COMMIT;
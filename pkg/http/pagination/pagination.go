@@ -0,0 +1,94 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/Markard/wordka/pkg/http/response"
+	"net/http"
+	"strconv"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+
+	cursorParam = "cursor"
+	limitParam  = "limit"
+)
+
+// Page describes a keyset page: items with an id lower than Cursor, newest first.
+// A zero Cursor means the first page.
+type Page struct {
+	Cursor int64
+	Limit  int
+}
+
+// FromRequest reads the 'cursor' and 'limit' query parameters of the request.
+func FromRequest(r *http.Request) (*Page, *response.ValidationError) {
+	page := &Page{Limit: DefaultLimit}
+	valErr := response.NewValidationError()
+	query := r.URL.Query()
+
+	if cursor := query.Get(cursorParam); cursor != "" {
+		id, err := DecodeCursor(cursor)
+		if err != nil {
+			valErr.AddFieldError(cursorParam, fmt.Sprintf("The '%s' parameter is malformed.", cursorParam))
+		}
+		page.Cursor = id
+	}
+
+	if limit := query.Get(limitParam); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 || l > MaxLimit {
+			valErr.AddFieldError(limitParam, fmt.Sprintf("The '%s' parameter must be between 1 and %d.", limitParam, MaxLimit))
+		}
+		page.Limit = l
+	}
+
+	if len(valErr.FieldErrors) > 0 {
+		return nil, valErr
+	}
+
+	return page, nil
+}
+
+func EncodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func DecodeCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if id <= 0 {
+		return 0, fmt.Errorf("cursor must be positive, got %d", id)
+	}
+
+	return id, nil
+}
+
+type Response[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// NewResponse builds a page from items fetched with a limit one greater than the page limit:
+// the extra item only signals that there is a next page and is not returned.
+func NewResponse[T any](items []T, limit int, idOf func(T) int64) *Response[T] {
+	resp := &Response[T]{Items: items}
+	if len(items) > limit {
+		resp.Items = items[:limit]
+		nextCursor := EncodeCursor(idOf(resp.Items[limit-1]))
+		resp.NextCursor = &nextCursor
+	}
+	if resp.Items == nil {
+		resp.Items = make([]T, 0)
+	}
+
+	return resp
+}
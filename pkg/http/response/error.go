@@ -2,7 +2,10 @@ package response
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type HttpError struct {
@@ -46,3 +49,10 @@ func ErrHttpError(w http.ResponseWriter, statusCode int, msg string) {
 	httpError := NewHttpError(statusCode, msg)
 	replyAsJson(w, httpError)
 }
+
+// ErrTooManyRequests tells the client to slow down and when to retry, in whole seconds rounded up.
+func ErrTooManyRequests(w http.ResponseWriter, retryAfter time.Duration, msg string) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	httpError := NewHttpError(http.StatusTooManyRequests, msg)
+	replyAsJson(w, httpError)
+}
@@ -71,6 +71,12 @@ func msgForTag(tag, fieldForErrMsg, param, originErrMessage string) string {
 		return fmt.Sprintf("The '%s' field may not be greater than %v.", fieldForErrMsg, param)
 	case "len":
 		return fmt.Sprintf("The '%s' field must be %v characters.", fieldForErrMsg, param)
+	case "oneof":
+		return fmt.Sprintf("The '%s' field must be one of: %s.", fieldForErrMsg, strings.ReplaceAll(param, " ", ", "))
+	case "datetime":
+		return fmt.Sprintf("The '%s' field must match the %s format.", fieldForErrMsg, param)
+	case "alphanum":
+		return fmt.Sprintf("The '%s' field may only contain letters and numbers.", fieldForErrMsg)
 	case "email":
 		return fmt.Sprintf("The '%s' field must be a valid email address.", fieldForErrMsg)
 	case "validate_password":
@@ -0,0 +1,100 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+)
+
+// leeway tolerates the clock skew between the provider and the app.
+const leeway = time.Minute
+
+// NewCodeVerifier returns a random PKCE code verifier, RFC 7636. It is kept by the app
+// and only its challenge is sent to the provider with the authorization request.
+func NewCodeVerifier() string {
+	return randomString(32)
+}
+
+// CodeChallenge is the S256 challenge of the code verifier.
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// NewNonce returns a random value binding the ID token to the authorization request.
+func NewNonce() string {
+	return randomString(16)
+}
+
+func (p *Provider) verify(ctx context.Context, ep *endpoints, idToken string, nonce string) (*Claims, error) {
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.keys.key(ctx, kid)
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(
+		idToken,
+		claims,
+		keyFunc,
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "PS256"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithIssuer(ep.Issuer),
+		jwt.WithAudience(p.config.ClientId),
+		jwt.WithLeeway(leeway),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIdToken, err)
+	}
+
+	tokenNonce, _ := claims["nonce"].(string)
+	if subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce does not match", ErrInvalidIdToken)
+	}
+	// A token issued to several clients names the one it was requested by.
+	if azp, ok := claims["azp"].(string); ok && azp != p.config.ClientId {
+		return nil, fmt.Errorf("%w: issued to %s", ErrInvalidIdToken, azp)
+	}
+
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return nil, fmt.Errorf("%w: no sub claim", ErrInvalidIdToken)
+	}
+	email, _ := claims["email"].(string)
+	name, _ := claims["name"].(string)
+	if name == "" {
+		name, _ = claims["preferred_username"].(string)
+	}
+
+	return &Claims{
+		Subject:       sub,
+		Email:         email,
+		EmailVerified: isTrue(claims["email_verified"]),
+		Name:          name,
+	}, nil
+}
+
+// isTrue reads a boolean claim, which some providers send as a string.
+func isTrue(claim any) bool {
+	switch v := claim.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
+
+func randomString(size int) string {
+	b := make([]byte, size)
+	_, _ = rand.Read(b)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,213 @@
+// Package mock is an OpenID Connect provider for local development and tests. It logs everybody
+// in without asking: as the default user, or as the email given in the login_hint parameter.
+package mock
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	keyId   = "mock"
+	codeTtl = time.Minute
+	idTtl   = 5 * time.Minute
+)
+
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Server serves the discovery document, the authorization, token and key endpoints at the root of Issuer.
+type Server struct {
+	// Issuer is the URL the server is reachable at, it may be set once the server is listening.
+	Issuer string
+	User   User
+
+	key   *rsa.PrivateKey
+	mu    sync.Mutex
+	codes map[string]*grant
+}
+
+type grant struct {
+	clientId      string
+	redirectUrl   string
+	nonce         string
+	codeChallenge string
+	user          User
+	expiresAt     time.Time
+}
+
+func NewServer(issuer string, user User) (*Server, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{Issuer: issuer, User: user, key: key, codes: map[string]*grant{}}, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/.well-known/openid-configuration":
+		s.discovery(w)
+	case "/authorize":
+		s.authorize(w, r)
+	case "/token":
+		s.token(w, r)
+	case "/jwks":
+		s.jwks(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) discovery(w http.ResponseWriter) {
+	issuer := strings.TrimSuffix(s.Issuer, "/")
+	writeJson(w, http.StatusOK, map[string]any{
+		"issuer":                                s.Issuer,
+		"authorization_endpoint":                issuer + "/authorize",
+		"token_endpoint":                        issuer + "/token",
+		"jwks_uri":                              issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+// authorize approves the request at once and redirects back with the code.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	redirectUrl, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || !redirectUrl.IsAbs() {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if query.Get("response_type") != "code" || query.Get("client_id") == "" ||
+		query.Get("code_challenge") == "" || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "the code flow with an S256 code challenge is required", http.StatusBadRequest)
+		return
+	}
+
+	user := s.User
+	if hint := query.Get("login_hint"); hint != "" {
+		user = User{Subject: hint, Email: hint, EmailVerified: true, Name: strings.Split(hint, "@")[0]}
+	}
+
+	code := randomString(16)
+	s.mu.Lock()
+	s.codes[code] = &grant{
+		clientId:      query.Get("client_id"),
+		redirectUrl:   redirectUrl.String(),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		user:          user,
+		expiresAt:     time.Now().Add(codeTtl),
+	}
+	s.mu.Unlock()
+
+	params := redirectUrl.Query()
+	params.Set("code", code)
+	params.Set("state", query.Get("state"))
+	redirectUrl.RawQuery = params.Encode()
+	http.Redirect(w, r, redirectUrl.String(), http.StatusFound)
+}
+
+// token exchanges a code once, checking the code verifier against the challenge of the authorization.
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type")
+		return
+	}
+
+	s.mu.Lock()
+	g, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	switch {
+	case !ok || time.Now().After(g.expiresAt):
+		tokenError(w, "invalid_grant")
+		return
+	case g.clientId != r.PostForm.Get("client_id") || g.redirectUrl != r.PostForm.Get("redirect_uri"):
+		tokenError(w, "invalid_grant")
+		return
+	case g.codeChallenge != base64.RawURLEncoding.EncodeToString(sum[:]):
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            s.Issuer,
+		"aud":            g.clientId,
+		"sub":            g.user.Subject,
+		"email":          g.user.Email,
+		"email_verified": g.user.EmailVerified,
+		"name":           g.user.Name,
+		"iat":            now.Unix(),
+		"exp":            now.Add(idTtl).Unix(),
+	}
+	if g.nonce != "" {
+		claims["nonce"] = g.nonce
+	}
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = keyId
+	signed, err := idToken.SignedString(s.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJson(w, http.StatusOK, map[string]any{
+		"access_token": randomString(16),
+		"token_type":   "Bearer",
+		"expires_in":   int(idTtl.Seconds()),
+		"id_token":     signed,
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter) {
+	pub := s.key.PublicKey
+	writeJson(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyId,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJson(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJson(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func randomString(size int) string {
+	b := make([]byte, size)
+	_, _ = rand.Read(b)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
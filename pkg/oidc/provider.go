@@ -0,0 +1,205 @@
+// Package oidc logs users in with external OpenID Connect providers using the authorization code flow
+// with PKCE. It discovers the endpoints of a provider, exchanges the code for an ID token
+// and verifies the token with the keys the provider publishes.
+package oidc
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrExchangeFailed = errors.New("authorization code exchange failed")
+	ErrInvalidIdToken = errors.New("ID token is invalid")
+)
+
+// Config is a client registered with a provider. The endpoints are discovered from the issuer
+// unless they are set.
+type Config struct {
+	Issuer       string
+	ClientId     string
+	ClientSecret string
+	RedirectUrl  string
+	Scopes       []string
+
+	AuthorizationUrl string
+	TokenUrl         string
+	JwksUrl          string
+}
+
+// Claims are what the provider tells about the user in the ID token.
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu        sync.Mutex
+	endpoints *endpoints
+	keys      *keyCache
+}
+
+type endpoints struct {
+	Issuer           string `json:"issuer"`
+	AuthorizationUrl string `json:"authorization_endpoint"`
+	TokenUrl         string `json:"token_endpoint"`
+	JwksUrl          string `json:"jwks_uri"`
+}
+
+func NewProvider(config Config, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &Provider{config: config, client: client}
+}
+
+// AuthCodeUrl is the page of the provider the user is sent to for the login. The provider redirects
+// back to the RedirectUrl with the code and the state, the nonce comes back in the ID token.
+func (p *Provider) AuthCodeUrl(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	ep, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authUrl, err := url.Parse(ep.AuthorizationUrl)
+	if err != nil {
+		return "", err
+	}
+	query := authUrl.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientId)
+	query.Set("redirect_uri", p.config.RedirectUrl)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authUrl.RawQuery = query.Encode()
+
+	return authUrl.String(), nil
+}
+
+// Exchange trades the code for the ID token of the user and verifies it. The verifier has to be the one
+// the code challenge was made of and the nonce the one sent along with it.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Claims, error) {
+	ep, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectUrl)
+	form.Set("code_verifier", codeVerifier)
+	form.Set("client_id", p.config.ClientId)
+	if p.config.ClientSecret != "" {
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.TokenUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var token struct {
+		IdToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&token); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExchangeFailed, err)
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("%w: %s %s", ErrExchangeFailed, token.Error, token.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrExchangeFailed, resp.StatusCode)
+	}
+	if token.IdToken == "" {
+		return nil, fmt.Errorf("%w: no id_token in the response", ErrExchangeFailed)
+	}
+
+	return p.verify(ctx, ep, token.IdToken, nonce)
+}
+
+// discover loads the endpoints of the provider once, those set in the config take precedence.
+func (p *Provider) discover(ctx context.Context) (*endpoints, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.endpoints != nil {
+		return p.endpoints, nil
+	}
+
+	ep := &endpoints{
+		Issuer:           p.config.Issuer,
+		AuthorizationUrl: p.config.AuthorizationUrl,
+		TokenUrl:         p.config.TokenUrl,
+		JwksUrl:          p.config.JwksUrl,
+	}
+	if ep.AuthorizationUrl == "" || ep.TokenUrl == "" || ep.JwksUrl == "" {
+		discovered := &endpoints{}
+		wellKnown := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+		if err := p.getJson(ctx, wellKnown, discovered); err != nil {
+			return nil, fmt.Errorf("discovery of %s: %w", p.config.Issuer, err)
+		}
+		if discovered.Issuer != p.config.Issuer {
+			return nil, fmt.Errorf("discovery of %s: issuer %s does not match", p.config.Issuer, discovered.Issuer)
+		}
+		ep.AuthorizationUrl = cmp.Or(ep.AuthorizationUrl, discovered.AuthorizationUrl)
+		ep.TokenUrl = cmp.Or(ep.TokenUrl, discovered.TokenUrl)
+		ep.JwksUrl = cmp.Or(ep.JwksUrl, discovered.JwksUrl)
+	}
+
+	p.endpoints = ep
+	p.keys = newKeyCache(ep.JwksUrl, p.getJson)
+
+	return ep, nil
+}
+
+func (p *Provider) getJson(ctx context.Context, target string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", target, resp.StatusCode)
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
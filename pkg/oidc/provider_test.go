@@ -0,0 +1,201 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/Markard/wordka/pkg/oidc/mock"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const (
+	clientId    = "wordka"
+	redirectUrl = "https://wordka.test/oauth/callback"
+)
+
+func TestProviderCodeFlow(t *testing.T) {
+	user := mock.User{Subject: "42", Email: "player@example.com", EmailVerified: true, Name: "Игрок"}
+	provider, _ := newMockProvider(t, user)
+
+	codeVerifier, nonce := NewCodeVerifier(), NewNonce()
+	code := authorize(t, provider, "state", nonce, codeVerifier)
+
+	claims, err := provider.Exchange(context.Background(), code, codeVerifier, nonce)
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	want := Claims{Subject: user.Subject, Email: user.Email, EmailVerified: true, Name: user.Name}
+	if *claims != want {
+		t.Errorf("Exchange() = %+v, want %+v", *claims, want)
+	}
+
+	if _, err = provider.Exchange(context.Background(), code, codeVerifier, nonce); !errors.Is(err, ErrExchangeFailed) {
+		t.Errorf("Exchange() of a used code error = %v, want %v", err, ErrExchangeFailed)
+	}
+}
+
+func TestProviderRejectsWrongCodeVerifier(t *testing.T) {
+	provider, _ := newMockProvider(t, mock.User{Subject: "42", Email: "player@example.com"})
+
+	nonce := NewNonce()
+	code := authorize(t, provider, "state", nonce, NewCodeVerifier())
+
+	_, err := provider.Exchange(context.Background(), code, NewCodeVerifier(), nonce)
+	if !errors.Is(err, ErrExchangeFailed) {
+		t.Errorf("Exchange() error = %v, want %v", err, ErrExchangeFailed)
+	}
+}
+
+func TestProviderRejectsWrongNonce(t *testing.T) {
+	provider, _ := newMockProvider(t, mock.User{Subject: "42", Email: "player@example.com"})
+
+	codeVerifier := NewCodeVerifier()
+	code := authorize(t, provider, "state", NewNonce(), codeVerifier)
+
+	_, err := provider.Exchange(context.Background(), code, codeVerifier, NewNonce())
+	if !errors.Is(err, ErrInvalidIdToken) {
+		t.Errorf("Exchange() error = %v, want %v", err, ErrInvalidIdToken)
+	}
+}
+
+func TestProviderVerifiesIdToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  func(claims jwt.MapClaims)
+		wantErr error
+	}{
+		{name: "valid", tamper: func(jwt.MapClaims) {}},
+		{name: "issued to another client", tamper: func(c jwt.MapClaims) { c["aud"] = "another" }, wantErr: ErrInvalidIdToken},
+		{
+			name:   "one of the audiences",
+			tamper: func(c jwt.MapClaims) { c["aud"] = []string{"another", clientId}; c["azp"] = clientId },
+		},
+		{
+			name:    "authorized party is another client",
+			tamper:  func(c jwt.MapClaims) { c["aud"] = []string{"another", clientId}; c["azp"] = "another" },
+			wantErr: ErrInvalidIdToken,
+		},
+		{name: "another issuer", tamper: func(c jwt.MapClaims) { c["iss"] = "https://evil.test" }, wantErr: ErrInvalidIdToken},
+		{name: "expired", tamper: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }, wantErr: ErrInvalidIdToken},
+		{name: "no nonce", tamper: func(c jwt.MapClaims) { delete(c, "nonce") }, wantErr: ErrInvalidIdToken},
+		{name: "no subject", tamper: func(c jwt.MapClaims) { delete(c, "sub") }, wantErr: ErrInvalidIdToken},
+		{name: "email verified as a string", tamper: func(c jwt.MapClaims) { c["email_verified"] = "true" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newTamperingProvider(t, tt.tamper)
+
+			claims, err := provider.Exchange(context.Background(), "code", "verifier", "nonce")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Exchange() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (claims.Subject != "42" || !claims.EmailVerified) {
+				t.Errorf("Exchange() = %+v", claims)
+			}
+		})
+	}
+}
+
+func newMockProvider(t *testing.T, user mock.User) (*Provider, *mock.Server) {
+	t.Helper()
+	server, err := mock.NewServer("", user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	server.Issuer = httpServer.URL
+
+	return NewProvider(Config{Issuer: httpServer.URL, ClientId: clientId, RedirectUrl: redirectUrl}, nil), server
+}
+
+// authorize goes through the login at the provider and returns the code it redirects back with.
+func authorize(t *testing.T, provider *Provider, state, nonce, codeVerifier string) string {
+	t.Helper()
+	authUrl, err := provider.AuthCodeUrl(context.Background(), state, nonce, CodeChallenge(codeVerifier))
+	if err != nil {
+		t.Fatalf("AuthCodeUrl() error = %v", err)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorization answered %s, want a redirect", resp.Status)
+	}
+	if got := location.Query().Get("state"); got != state {
+		t.Errorf("state = %s, want %s", got, state)
+	}
+
+	return location.Query().Get("code")
+}
+
+// newTamperingProvider is a provider issuing an ID token for any code, with the claims changed by tamper.
+func newTamperingProvider(t *testing.T, tamper func(claims jwt.MapClaims)) *Provider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var issuer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"issuer":                 issuer,
+				"authorization_endpoint": issuer + "/authorize",
+				"token_endpoint":         issuer + "/token",
+				"jwks_uri":               issuer + "/jwks",
+			})
+		case "/jwks":
+			_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}}})
+		case "/token":
+			now := time.Now()
+			claims := jwt.MapClaims{
+				"iss":            issuer,
+				"aud":            clientId,
+				"sub":            "42",
+				"email":          "player@example.com",
+				"email_verified": true,
+				"nonce":          "nonce",
+				"iat":            now.Unix(),
+				"exp":            now.Add(time.Minute).Unix(),
+			}
+			tamper(claims)
+			idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+			idToken.Header["kid"] = "test"
+			signed, err := idToken.SignedString(key)
+			if err != nil {
+				t.Error(err)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"id_token": signed})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	issuer = server.URL
+
+	return NewProvider(Config{Issuer: issuer, ClientId: clientId, RedirectUrl: redirectUrl}, nil)
+}
@@ -0,0 +1,113 @@
+// Package bezbukv harvests nouns of one length from the listing pages of bezbukv.ru.
+package bezbukv
+
+import (
+	"context"
+	"fmt"
+	"github.com/Markard/wordka/pkg/wordsource"
+	"github.com/PuerkitoBio/goquery"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	Name           = "bezbukv"
+	DefaultBaseUrl = "https://bezbukv.ru"
+)
+
+var pageLinkPattern = regexp.MustCompile(`[?&]page=(\d+)`)
+
+type Source struct {
+	fetcher *wordsource.HttpFetcher
+	baseUrl string
+	length  int
+	pages   int
+	pattern *regexp.Regexp
+}
+
+// New creates the source of the nouns of the length. With zero pages the number of pages
+// is discovered from the pagination of the first one.
+func New(fetcher *wordsource.HttpFetcher, baseUrl string, length int, pages int) *Source {
+	return &Source{
+		fetcher: fetcher,
+		baseUrl: strings.TrimRight(baseUrl, "/"),
+		length:  length,
+		pages:   pages,
+		pattern: Pattern(length),
+	}
+}
+
+// Pattern matches a line of a listing holding a single word of the length.
+func Pattern(length int) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`^[а-яА-ЯёЁ-]{%d}$`, length))
+}
+
+func (s *Source) Name() string {
+	return Name
+}
+
+// ListingUrl is the address of the first listing page, identifying what is being harvested.
+func (s *Source) ListingUrl() string {
+	return s.baseUrl + "/mask/" + strings.Repeat("%2A", s.length) + "/noun"
+}
+
+func (s *Source) Paginate(ctx context.Context) ([]wordsource.Page, error) {
+	pages := s.pages
+	if pages == 0 {
+		discovered, err := s.discoverPages(ctx)
+		if err != nil {
+			return nil, err
+		}
+		pages = discovered
+	}
+
+	listing := make([]wordsource.Page, 0, pages)
+	for number := 1; number <= pages; number++ {
+		listing = append(listing, wordsource.Page{Number: number, Url: s.ListingUrl() + "?page=" + strconv.Itoa(number)})
+	}
+
+	return listing, nil
+}
+
+func (s *Source) Fetch(ctx context.Context, page wordsource.Page) (*goquery.Document, error) {
+	return s.fetcher.Fetch(ctx, page.Url)
+}
+
+func (s *Source) Extract(doc *goquery.Document) []string {
+	return Extract(doc, s.pattern)
+}
+
+// Extract picks the words matching the pattern out of the word columns of a listing page.
+func Extract(doc *goquery.Document, pattern *regexp.Regexp) []string {
+	words := make([]string, 0)
+	doc.Find("div.view").Each(func(_ int, view *goquery.Selection) {
+		for _, line := range strings.Split(view.Text(), "\n") {
+			if candidate := strings.TrimSpace(line); pattern.MatchString(candidate) {
+				words = append(words, candidate)
+			}
+		}
+	})
+
+	return words
+}
+
+// discoverPages reads the number of listing pages from the pagination links of the first one.
+func (s *Source) discoverPages(ctx context.Context) (int, error) {
+	doc, err := s.fetcher.Fetch(ctx, s.ListingUrl()+"?page=1")
+	if err != nil {
+		return 0, err
+	}
+
+	pages := 1
+	doc.Find("a[href]").Each(func(_ int, link *goquery.Selection) {
+		href, _ := link.Attr("href")
+		if matches := pageLinkPattern.FindStringSubmatch(href); matches != nil {
+			if page, err := strconv.Atoi(matches[1]); err == nil {
+				pages = max(pages, page)
+			}
+		}
+	})
+
+	return pages, nil
+}
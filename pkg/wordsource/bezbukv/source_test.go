@@ -0,0 +1,107 @@
+package bezbukv
+
+import (
+	"context"
+	"github.com/Markard/wordka/pkg/wordsource"
+	"github.com/PuerkitoBio/goquery"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestExtract(t *testing.T) {
+	doc := loadPage(t, "testdata/page1.html")
+
+	got := Extract(doc, Pattern(5))
+	want := []string{"абака", "абзац", "аборт", "Авеню", "аврал", "ёжики", "агнец"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Extract() = %v, want %v", got, want)
+	}
+}
+
+func TestPaginateDiscoversPages(t *testing.T) {
+	tests := []struct {
+		name  string
+		page  string
+		pages int
+		want  int
+	}{
+		{name: "highest page of the pagination", page: "testdata/page1.html", want: 12},
+		{name: "single page without pagination", page: "testdata/last.html", want: 1},
+		{name: "number of pages given", page: "testdata/page1.html", pages: 3, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := servePage(t, tt.page, &requests)
+			source := New(newFetcher(), server.URL, 5, tt.pages)
+
+			pages, err := source.Paginate(context.Background())
+			if err != nil {
+				t.Fatalf("Paginate() error = %v", err)
+			}
+			if len(pages) != tt.want {
+				t.Fatalf("Paginate() returned %d pages, want %d", len(pages), tt.want)
+			}
+			for i, page := range pages {
+				want := server.URL + "/mask/%2A%2A%2A%2A%2A/noun?page=" + strconv.Itoa(i+1)
+				if page.Number != i+1 || page.Url != want {
+					t.Errorf("page %d url = %s, want %s", page.Number, page.Url, want)
+				}
+			}
+			if tt.pages != 0 && requests != 0 {
+				t.Errorf("the site was requested %d times, want none", requests)
+			}
+		})
+	}
+}
+
+func servePage(t *testing.T, path string, requests *int) *httptest.Server {
+	t.Helper()
+	body, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if r.URL.Path != "/mask/*****/noun" || r.URL.Query().Get("page") != "1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func loadPage(t *testing.T, path string) *goquery.Document {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = file.Close() }()
+
+	doc, err := goquery.NewDocumentFromReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func newFetcher() *wordsource.HttpFetcher {
+	return wordsource.NewHttpFetcher(
+		wordsource.FetcherOptions{Timeout: time.Second},
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+}
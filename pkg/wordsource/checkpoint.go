@@ -0,0 +1,87 @@
+package wordsource
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// Checkpoint remembers the pages of a source whose words are saved already, so a crashed
+// or interrupted run resumes where it stopped instead of scraping everything again.
+type Checkpoint struct {
+	path string
+
+	Key  string       `json:"key"`
+	Done map[int]bool `json:"done"`
+}
+
+// LoadCheckpoint reads the checkpoint of the harvest identified by the key, e.g. the address of a listing.
+// A missing file or one left by another harvest starts from scratch.
+func LoadCheckpoint(path, key string) (*Checkpoint, error) {
+	checkpoint := &Checkpoint{path: path, Key: key, Done: make(map[int]bool)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	saved := &Checkpoint{}
+	if err := json.Unmarshal(data, saved); err != nil {
+		return nil, err
+	}
+	if saved.Key == key && saved.Done != nil {
+		checkpoint.Done = saved.Done
+	}
+
+	return checkpoint, nil
+}
+
+// Pending filters out the pages that are saved already.
+func (c *Checkpoint) Pending(pages []Page) []Page {
+	pending := make([]Page, 0, len(pages))
+	for _, page := range pages {
+		if !c.Done[page.Number] {
+			pending = append(pending, page)
+		}
+	}
+
+	return pending
+}
+
+func (c *Checkpoint) MarkDone(page Page) error {
+	c.Done[page.Number] = true
+
+	return c.save()
+}
+
+// Remove deletes the checkpoint once the whole source is harvested.
+func (c *Checkpoint) Remove() error {
+	err := os.Remove(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// save replaces the file atomically, so a crash while saving never leaves a broken checkpoint behind.
+func (c *Checkpoint) save() error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, c.path)
+}
@@ -0,0 +1,78 @@
+package wordsource
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCheckpointResumes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint", "bezbukv.json")
+	pages := []Page{{Number: 1}, {Number: 2}, {Number: 3}, {Number: 4}}
+
+	checkpoint, err := LoadCheckpoint(path, "listing")
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+	if got := checkpoint.Pending(pages); len(got) != len(pages) {
+		t.Fatalf("Pending() of a new checkpoint = %v, want every page", got)
+	}
+	for _, page := range []Page{pages[0], pages[2]} {
+		if err := checkpoint.MarkDone(page); err != nil {
+			t.Fatalf("MarkDone() error = %v", err)
+		}
+	}
+
+	resumed, err := LoadCheckpoint(path, "listing")
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+	got := resumed.Pending(pages)
+	if want := []Page{pages[1], pages[3]}; !slices.Equal(got, want) {
+		t.Errorf("Pending() after resuming = %v, want %v", got, want)
+	}
+
+	if err := resumed.Remove(); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("checkpoint file still exists: %v", err)
+	}
+	if err := resumed.Remove(); err != nil {
+		t.Errorf("Remove() of a removed checkpoint error = %v", err)
+	}
+}
+
+func TestCheckpointOfAnotherHarvestStartsFromScratch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bezbukv.json")
+	pages := []Page{{Number: 1}, {Number: 2}}
+
+	checkpoint, err := LoadCheckpoint(path, "five letters")
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+	if err := checkpoint.MarkDone(pages[0]); err != nil {
+		t.Fatalf("MarkDone() error = %v", err)
+	}
+
+	other, err := LoadCheckpoint(path, "six letters")
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+	if got := other.Pending(pages); !slices.Equal(got, pages) {
+		t.Errorf("Pending() = %v, want %v", got, pages)
+	}
+}
+
+func TestLoadCheckpointRejectsBrokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bezbukv.json")
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadCheckpoint(path, "listing"); err == nil {
+		t.Error("LoadCheckpoint() error = nil, want the decoding error")
+	}
+}
@@ -0,0 +1,129 @@
+package wordsource
+
+import (
+	"context"
+	"errors"
+	"github.com/PuerkitoBio/goquery"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	initialBackoff = time.Second
+	maxBackoff     = time.Minute
+	userAgent      = "wordka-parser (+https://github.com/Markard/wordka)"
+)
+
+type FetcherOptions struct {
+	Retries  int
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+// HttpFetcher loads pages of a web site politely. All callers share one request rate,
+// so adding workers never hits the site harder.
+type HttpFetcher struct {
+	client   *http.Client
+	retries  int
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu          sync.Mutex
+	nextRequest time.Time
+}
+
+// permanentError is a failure which retrying the request would not fix.
+type permanentError struct {
+	status string
+}
+
+func (e *permanentError) Error() string {
+	return "unexpected response: " + e.status
+}
+
+func NewHttpFetcher(options FetcherOptions, logger *slog.Logger) *HttpFetcher {
+	return &HttpFetcher{
+		client:   &http.Client{Timeout: options.Timeout},
+		retries:  options.Retries,
+		interval: options.Interval,
+		logger:   logger,
+	}
+}
+
+// Fetch gets a page, retrying network failures, throttling and server errors with an exponential backoff.
+func (f *HttpFetcher) Fetch(ctx context.Context, url string) (*goquery.Document, error) {
+	backoff := initialBackoff
+	for attempt := 0; ; attempt++ {
+		if err := f.throttle(ctx); err != nil {
+			return nil, err
+		}
+		f.logger.Info("WordSource:Fetch", "url", url, "attempt", attempt+1)
+
+		doc, retryAfter, err := f.get(ctx, url)
+		if err == nil {
+			return doc, nil
+		}
+		var permanentErr *permanentError
+		if errors.As(err, &permanentErr) || attempt >= f.retries || ctx.Err() != nil {
+			return nil, err
+		}
+
+		// Jitter keeps the workers from retrying in lockstep.
+		delay := max(backoff, retryAfter) + rand.N(backoff/2+1)
+		f.logger.Warn("WordSource:Retry", "url", url, "in", delay, "error", err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// get makes a single request. For a throttled one it also returns how long the server asked to wait.
+func (f *HttpFetcher) get(ctx context.Context, url string) (*goquery.Document, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, &permanentError{status: err.Error()}
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		doc, err := goquery.NewDocumentFromReader(resp.Body)
+		return doc, 0, err
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return nil, time.Duration(retryAfter) * time.Second, errors.New(resp.Status)
+	default:
+		return nil, 0, &permanentError{status: resp.Status}
+	}
+}
+
+// throttle waits for the next free slot, spacing the requests of all callers by the interval.
+func (f *HttpFetcher) throttle(ctx context.Context) error {
+	f.mu.Lock()
+	slot := f.nextRequest
+	if now := time.Now(); slot.Before(now) {
+		slot = now
+	}
+	f.nextRequest = slot.Add(f.interval)
+	f.mu.Unlock()
+
+	select {
+	case <-time.After(time.Until(slot)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
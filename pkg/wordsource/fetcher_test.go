@@ -0,0 +1,100 @@
+package wordsource
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchRetriesThrottledRequestAfterRetryAfter(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`<div class="view">город</div>`))
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	doc, err := newTestFetcher(3).Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if got := doc.Find("div.view").Text(); got != "город" {
+		t.Errorf("Fetch() page = %q, want %q", got, "город")
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+	// Retry-After outweighs the first backoff of a second.
+	if elapsed := time.Since(start); elapsed < 2*time.Second {
+		t.Errorf("retried after %s, want at least the 2s of Retry-After", elapsed)
+	}
+}
+
+func TestFetchGivesUpAfterRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := newTestFetcher(1).Fetch(context.Background(), server.URL)
+	if err == nil {
+		t.Fatal("Fetch() error = nil, want the server error")
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestFetchDoesNotRetryPermanentError(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	_, err := newTestFetcher(3).Fetch(context.Background(), server.URL)
+	var permanentErr *permanentError
+	if !errors.As(err, &permanentErr) {
+		t.Fatalf("Fetch() error = %v, want a permanent error", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestFetchStopsRetryingOnCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := newTestFetcher(3).Fetch(ctx, server.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Fetch() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func newTestFetcher(retries int) *HttpFetcher {
+	return NewHttpFetcher(
+		FetcherOptions{Retries: retries, Timeout: time.Second},
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+}
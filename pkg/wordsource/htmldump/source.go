@@ -0,0 +1,57 @@
+// Package htmldump harvests pages saved to a local directory, so a source can be replayed offline.
+package htmldump
+
+import (
+	"context"
+	"github.com/Markard/wordka/pkg/wordsource"
+	"github.com/PuerkitoBio/goquery"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+type Source struct {
+	name    string
+	dir     string
+	extract func(doc *goquery.Document) []string
+}
+
+// New creates a source of the *.html files in the directory. The pages are extracted the way
+// the site they were saved from is, and the words are attributed to that site by its name.
+func New(name string, dir string, extract func(doc *goquery.Document) []string) *Source {
+	return &Source{name: name, dir: dir, extract: extract}
+}
+
+func (s *Source) Name() string {
+	return s.name
+}
+
+// Paginate lists the saved pages in the order of their file names.
+func (s *Source) Paginate(_ context.Context) ([]wordsource.Page, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.html"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+
+	pages := make([]wordsource.Page, 0, len(files))
+	for i, file := range files {
+		pages = append(pages, wordsource.Page{Number: i + 1, Url: file})
+	}
+
+	return pages, nil
+}
+
+func (s *Source) Fetch(_ context.Context, page wordsource.Page) (*goquery.Document, error) {
+	file, err := os.Open(page.Url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	return goquery.NewDocumentFromReader(file)
+}
+
+func (s *Source) Extract(doc *goquery.Document) []string {
+	return s.extract(doc)
+}
@@ -0,0 +1,121 @@
+package wordsource
+
+import (
+	"context"
+	"sync"
+)
+
+// Harvest loads and extracts the pages with the number of workers. The channel is closed
+// once every page is processed or the context is cancelled.
+func Harvest(ctx context.Context, source Source, pages []Page, workers int) <-chan *Result {
+	jobs := make(chan Page)
+	results := make(chan *Result)
+	var wg sync.WaitGroup
+
+	for w := 1; w <= workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range jobs {
+				result := &Result{Source: source.Name(), Page: page}
+				doc, err := source.Fetch(ctx, page)
+				if ctx.Err() != nil {
+					return
+				}
+				if err != nil {
+					result.Err = err
+				} else {
+					result.Words = source.Extract(doc)
+				}
+				results <- result
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, page := range pages {
+			select {
+			case jobs <- page:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// Normalize is a pipeline stage bringing every word to its canonical spelling
+// and dropping the words the normalize function rejects.
+func Normalize(in <-chan *Result, normalize func(word string) (string, error)) <-chan *Result {
+	out := make(chan *Result)
+	go func() {
+		defer close(out)
+		for result := range in {
+			words := make([]string, 0, len(result.Words))
+			for _, word := range result.Words {
+				if normalized, err := normalize(word); err == nil {
+					words = append(words, normalized)
+				}
+			}
+			result.Words = words
+			out <- result
+		}
+	}()
+
+	return out
+}
+
+// SavedWords remembers the words saved during a run. The words are added once saving them succeeds,
+// so the words of a page that failed to save are not dropped from the pages harvested later.
+type SavedWords struct {
+	mu    sync.Mutex
+	words map[string]bool
+}
+
+func NewSavedWords() *SavedWords {
+	return &SavedWords{words: make(map[string]bool)}
+}
+
+func (s *SavedWords) Add(words []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, word := range words {
+		s.words[word] = true
+	}
+}
+
+func (s *SavedWords) Has(word string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.words[word]
+}
+
+// Dedup is a pipeline stage dropping the words saved already and the repeats of a word on the same page.
+func Dedup(in <-chan *Result, saved *SavedWords) <-chan *Result {
+	out := make(chan *Result)
+	go func() {
+		defer close(out)
+		for result := range in {
+			seen := make(map[string]bool, len(result.Words))
+			words := make([]string, 0, len(result.Words))
+			for _, word := range result.Words {
+				if !seen[word] && !saved.Has(word) {
+					seen[word] = true
+					words = append(words, word)
+				}
+			}
+			result.Words = words
+			out <- result
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,79 @@
+package wordsource
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	normalize := func(word string) (string, error) {
+		if strings.Contains(word, "-") {
+			return "", errors.New("not a word")
+		}
+		return strings.ReplaceAll(strings.ToLower(word), "ё", "е"), nil
+	}
+	in := make(chan *Result, 2)
+	in <- &Result{Page: Page{Number: 1}, Words: []string{"Ёлка", "альфа-", "город"}}
+	in <- &Result{Page: Page{Number: 2}, Err: errors.New("not loaded")}
+	close(in)
+
+	results := collect(Normalize(in, normalize))
+	if len(results) != 2 {
+		t.Fatalf("Normalize() passed %d results, want 2", len(results))
+	}
+	if want := []string{"елка", "город"}; !slices.Equal(results[0].Words, want) {
+		t.Errorf("words = %v, want %v", results[0].Words, want)
+	}
+	if results[1].Err == nil || len(results[1].Words) != 0 {
+		t.Errorf("failed page = %+v, want its error and no words", results[1])
+	}
+}
+
+func TestDedup(t *testing.T) {
+	saved := NewSavedWords()
+	saved.Add([]string{"книга"})
+	in := make(chan *Result, 1)
+	in <- &Result{Words: []string{"город", "книга", "город", "почта"}}
+	close(in)
+
+	results := collect(Dedup(in, saved))
+	if want := []string{"город", "почта"}; !slices.Equal(results[0].Words, want) {
+		t.Errorf("words = %v, want %v", results[0].Words, want)
+	}
+}
+
+func TestDedupPassesWordsUntilSaved(t *testing.T) {
+	saved := NewSavedWords()
+	in := make(chan *Result)
+	out := Dedup(in, saved)
+
+	// The words of the first page fail to save, so the second page still carries them.
+	in <- &Result{Page: Page{Number: 1}, Words: []string{"город", "книга"}}
+	first := <-out
+	in <- &Result{Page: Page{Number: 2}, Words: []string{"город", "почта"}}
+	second := <-out
+	if want := []string{"город", "почта"}; !slices.Equal(second.Words, want) {
+		t.Errorf("words after a failed save = %v, want %v", second.Words, want)
+	}
+
+	saved.Add(second.Words)
+	in <- &Result{Page: Page{Number: 3}, Words: []string{"город", "книга", "почта"}}
+	third := <-out
+	close(in)
+	if want := []string{"книга"}; !slices.Equal(third.Words, want) {
+		t.Errorf("words after a successful save = %v, want %v", third.Words, want)
+	}
+	if want := []string{"город", "книга"}; !slices.Equal(first.Words, want) {
+		t.Errorf("words of the first page = %v, want %v", first.Words, want)
+	}
+}
+
+func collect(results <-chan *Result) []*Result {
+	collected := make([]*Result, 0)
+	for result := range results {
+		collected = append(collected, result)
+	}
+	return collected
+}
@@ -0,0 +1,34 @@
+// Package wordsource harvests candidate words from web sites and local page dumps.
+// Every source is a small adapter telling which pages to harvest, how to load a page and
+// how to pick the words out of it, the package takes care of the rest.
+package wordsource
+
+import (
+	"context"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Page is a single page of a source, addressed by a URL or by a file path for local dumps.
+type Page struct {
+	Number int
+	Url    string
+}
+
+type Source interface {
+	// Name identifies the source in the provenance of the words found in it.
+	Name() string
+	// Paginate lists the pages to harvest.
+	Paginate(ctx context.Context) ([]Page, error)
+	// Fetch loads a page.
+	Fetch(ctx context.Context, page Page) (*goquery.Document, error)
+	// Extract picks the candidate words out of a loaded page.
+	Extract(doc *goquery.Document) []string
+}
+
+// Result holds the words found on a page, or the reason the page could not be harvested.
+type Result struct {
+	Source string
+	Page   Page
+	Words  []string
+	Err    error
+}